@@ -12,18 +12,29 @@ import (
 
 	"backend/internal/config"
 	"backend/internal/db"
+	"backend/internal/domain/outbox"
+	"backend/internal/events"
 	"backend/internal/logger"
 	"backend/internal/repository"
 	"backend/internal/service"
+	"backend/internal/storage"
 	httptransport "backend/internal/transport/http"
+	"backend/internal/worker"
 )
 
-// Application 聚合服务所需的全部依赖并负责启动 HTTP 服务。
+// Application 聚合服务所需的全部依赖并负责启动 HTTP 服务与后台任务。
 type Application struct {
-	cfg    config.Config
-	log    *zap.Logger
-	db     *sql.DB
-	server *http.Server
+	cfg       config.Config
+	log       *zap.Logger
+	db        *sql.DB
+	server    *http.Server
+	events    *events.Broker
+	deadlines *worker.DeadlineWorker
+	schedules *worker.ScheduleWorker
+	webhooks  *worker.WebhookWorker
+	chats     *worker.ChatWorker
+	outbox    *worker.OutboxDispatcher
+	emails    *worker.EmailWorker
 }
 
 // New 构造应用实例。
@@ -52,10 +63,26 @@ func New(ctx context.Context) (*Application, error) {
 		return nil, fmt.Errorf("run migrations: %w", err)
 	}
 
+	blobs, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("init storage: %w", err)
+	}
+
+	broker := events.NewBroker(cfg.Events, dbConn, cfg.DB.DSN, log)
+
 	repos := repository.NewRegistry(dbConn)
-	services := service.NewRegistry(cfg, repos, log)
+	services := service.NewRegistry(cfg, repos, blobs, log)
+
+	// 任务变更随事务写入发件箱，由分发器推送给 SSE 客户端，生成站内通知，并生成 webhook 投递与群机器人推送记录。
+	// 目标名称记录在发件箱中用于跳过已送达的目标，修改名称会导致重试中的事件被重复投递。
+	sinks := []outbox.Target{
+		{Name: "events", Sink: broker},
+		{Name: "notifications", Sink: outbox.SinkFunc(services.Notifications.HandleTaskEvent)},
+		{Name: "webhooks", Sink: outbox.SinkFunc(services.Webhooks.Enqueue)},
+		{Name: "chat", Sink: outbox.SinkFunc(services.Chat.Announce)},
+	}
 
-	router := httptransport.NewRouter(cfg, services, log)
+	router := httptransport.NewRouter(cfg, services, broker, log)
 
 	server := &http.Server{
 		Addr:              cfg.Server.Addr,
@@ -65,17 +92,34 @@ func New(ctx context.Context) (*Application, error) {
 		IdleTimeout:       cfg.Server.IdleTimeout,
 		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
 	}
+	// SSE 长连接不会自行结束，关闭服务时先断开它们，否则 Shutdown 会一直等到超时。
+	server.RegisterOnShutdown(broker.CloseSubscribers)
 
 	return &Application{
-		cfg:    cfg,
-		log:    log,
-		db:     dbConn,
-		server: server,
+		cfg:       cfg,
+		log:       log,
+		db:        dbConn,
+		server:    server,
+		events:    broker,
+		deadlines: worker.NewDeadlineWorker(cfg.Deadline, services.Tasks, services.Notifications, log),
+		schedules: worker.NewScheduleWorker(cfg.Schedule, services.Schedules, log),
+		webhooks:  worker.NewWebhookWorker(cfg.Webhook, services.Webhooks, log),
+		chats:     worker.NewChatWorker(cfg.Chat, services.Chat, log),
+		outbox:    worker.NewOutboxDispatcher(cfg.Outbox, repos.Outbox, sinks, log),
+		emails:    worker.NewEmailWorker(cfg.Mail, services.Emails, log),
 	}, nil
 }
 
-// Run 启动 HTTP 服务。
+// Run 启动后台任务与 HTTP 服务。
 func (a *Application) Run() error {
+	a.events.Start(context.Background())
+	a.deadlines.Start(context.Background())
+	a.schedules.Start(context.Background())
+	a.webhooks.Start(context.Background())
+	a.chats.Start(context.Background())
+	a.outbox.Start(context.Background())
+	a.emails.Start(context.Background())
+
 	a.log.Info("server starting", zap.String("addr", a.server.Addr))
 	err := a.server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
@@ -93,6 +137,14 @@ func (a *Application) Shutdown(ctx context.Context) {
 	if a.server != nil {
 		_ = a.server.Shutdown(ctx)
 	}
+	// 后台任务依赖数据库连接，需在关闭连接池之前停止。
+	a.deadlines.Stop(ctx)
+	a.schedules.Stop(ctx)
+	a.outbox.Stop(ctx)
+	a.webhooks.Stop(ctx)
+	a.chats.Stop(ctx)
+	a.emails.Stop(ctx)
+	a.events.Stop(ctx)
 	if a.db != nil {
 		_ = a.db.Close()
 	}
@@ -0,0 +1,242 @@
+package chatbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain/chat"
+)
+
+const maxResponseBody = 4096
+
+// Field 是卡片中的一行“标签：内容”。
+type Field struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// Card 是推送到群聊的任务卡片，各平台按自身的 markdown 方言渲染；Highlight 为 true 时使用醒目配色。
+// Title 与 Field.Value 视为纯文本，渲染时转义 markdown；Link 与 Field.Label 由服务端生成，原样输出。
+type Card struct {
+	Title     string  `json:"title"`
+	Fields    []Field `json:"fields"`
+	Link      string  `json:"link,omitempty"`
+	Highlight bool    `json:"highlight,omitempty"`
+}
+
+// Client 通过群机器人 webhook 发送消息。
+type Client struct {
+	http *http.Client
+}
+
+// NewClient 构造机器人客户端，timeout 覆盖单次请求。
+func NewClient(timeout time.Duration) *Client {
+	return &Client{http: &http.Client{Timeout: timeout}}
+}
+
+// Send 将卡片按 provider 的格式发送到 webhookURL，secret 非空时按平台规则加签。
+// 平台在 HTTP 200 中返回业务错误码，非零时同样视为失败。
+func (c *Client) Send(ctx context.Context, provider chat.Provider, webhookURL, secret string, card Card) error {
+	target, body, err := Build(provider, webhookURL, secret, card, time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    int    `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if len(raw) > 0 && json.Unmarshal(raw, &result) == nil {
+		if result.ErrCode != 0 {
+			return fmt.Errorf("%s error %d: %s", provider, result.ErrCode, result.ErrMsg)
+		}
+		if result.Code != 0 {
+			return fmt.Errorf("%s error %d: %s", provider, result.Code, result.Msg)
+		}
+	}
+	return nil
+}
+
+// Build 生成请求地址与请求体。钉钉的签名附加在地址参数中，飞书的签名写入请求体。
+func Build(provider chat.Provider, webhookURL, secret string, card Card, now time.Time) (string, []byte, error) {
+	var payload map[string]any
+	switch provider {
+	case chat.ProviderWeCom:
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"content": renderWeCom(card)},
+		}
+	case chat.ProviderDingTalk:
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"title": plain(card.Title), "text": renderDingTalk(card)},
+		}
+		if secret != "" {
+			signed, err := signDingTalk(webhookURL, secret, now)
+			if err != nil {
+				return "", nil, err
+			}
+			webhookURL = signed
+		}
+	case chat.ProviderFeishu:
+		payload = map[string]any{
+			"msg_type": "interactive",
+			"card":     renderFeishu(card),
+		}
+		if secret != "" {
+			timestamp := strconv.FormatInt(now.Unix(), 10)
+			payload["timestamp"] = timestamp
+			payload["sign"] = signFeishu(timestamp, secret)
+		}
+	default:
+		return "", nil, fmt.Errorf("unsupported chat provider %q", provider)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+	return webhookURL, body, nil
+}
+
+func renderWeCom(card Card) string {
+	var b strings.Builder
+	title := inline(card.Title)
+	if card.Highlight {
+		title = `<font color="warning">` + title + `</font>`
+	}
+	b.WriteString("## " + title + "\n")
+	for _, f := range card.Fields {
+		b.WriteString("> " + f.Label + "：<font color=\"comment\">" + inline(f.Value) + "</font>\n")
+	}
+	if card.Link != "" {
+		b.WriteString("\n[查看任务](" + card.Link + ")")
+	}
+	return b.String()
+}
+
+func renderDingTalk(card Card) string {
+	var b strings.Builder
+	b.WriteString("### " + inline(card.Title) + "\n\n")
+	for _, f := range card.Fields {
+		b.WriteString("- **" + f.Label + "**：" + inline(f.Value) + "\n")
+	}
+	if card.Link != "" {
+		b.WriteString("\n[查看任务](" + card.Link + ")")
+	}
+	return b.String()
+}
+
+func renderFeishu(card Card) map[string]any {
+	template := "blue"
+	if card.Highlight {
+		template = "red"
+	}
+	lines := make([]string, 0, len(card.Fields))
+	for _, f := range card.Fields {
+		lines = append(lines, "**"+f.Label+"**："+inline(f.Value))
+	}
+	elements := []any{
+		map[string]any{"tag": "markdown", "content": strings.Join(lines, "\n")},
+	}
+	if card.Link != "" {
+		elements = append(elements, map[string]any{
+			"tag": "action",
+			"actions": []any{map[string]any{
+				"tag":  "button",
+				"text": map[string]any{"tag": "plain_text", "content": "查看任务"},
+				"type": "primary",
+				"url":  card.Link,
+			}},
+		})
+	}
+	return map[string]any{
+		"config": map[string]any{"wide_screen_mode": true},
+		"header": map[string]any{
+			"template": template,
+			"title":    map[string]any{"tag": "plain_text", "content": plain(card.Title)},
+		},
+		"elements": elements,
+	}
+}
+
+// signDingTalk 按钉钉加签规则在地址后追加 timestamp（毫秒）与 sign=Base64(HMAC-SHA256(secret, "timestamp\nsecret"))。
+func signDingTalk(webhookURL, secret string, now time.Time) (string, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	query := parsed.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// signFeishu 按飞书加签规则以 "timestamp\nsecret" 为密钥对空串做 HMAC-SHA256，timestamp 为秒。
+func signFeishu(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// markdownEscaper 转义 markdown 与 HTML 标记，使用户输入无法构造链接、图片、强调、<font>/<at> 标签；
+// @ 后插入零宽空格，避免任务标题或标签在群内触发 @ 提醒。
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"#", `\#`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+	"!", `\!`,
+	"|", `\|`,
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"@", "@\u200b",
+)
+
+// plain 将换行压成空格，避免用户输入的内容打乱卡片排版，用于平台按纯文本展示的字段。
+func plain(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// inline 在 plain 的基础上转义 markdown，用于写入 markdown 正文的用户内容。
+func inline(s string) string {
+	return markdownEscaper.Replace(plain(s))
+}
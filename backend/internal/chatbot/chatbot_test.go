@@ -0,0 +1,84 @@
+package chatbot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain/chat"
+)
+
+func TestInlineEscapesMarkdown(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"普通标题", "普通标题"},
+		{"多行\n标题\t内容", "多行 标题 内容"},
+		{"[点我](https://evil.example)", `\[点我\]\(https://evil.example\)`},
+		{"![img](x.png)", `\!\[img\]\(x.png\)`},
+		{"**加粗** _斜体_ `code`", "\\*\\*加粗\\*\\* \\_斜体\\_ \\`code\\`"},
+		{`<font color="warning">假</font>`, `&lt;font color="warning"&gt;假&lt;/font&gt;`},
+		{`<at user_id="all">所有人</at>`, `&lt;at user\_id="all"&gt;所有人&lt;/at&gt;`},
+		{"@所有人 @13800000000", "@​所有人 @​13800000000"},
+		{`a\b`, `a\\b`},
+	}
+	for _, tc := range cases {
+		if got := inline(tc.in); got != tc.want {
+			t.Errorf("inline(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestBuildEscapesUserContent(t *testing.T) {
+	card := Card{
+		Title:  "新任务发布：[钓鱼](https://evil.example) @所有人",
+		Fields: []Field{{Label: "标签", Value: "<at id=all></at>"}},
+		Link:   "https://ops.example.com/home?task=1",
+	}
+	// markdownOf 取出各平台按 markdown 渲染的正文；钉钉的 title 与飞书的卡片标题按纯文本展示，不在检查范围内。
+	markdownOf := func(provider chat.Provider, body []byte) string {
+		var payload struct {
+			Markdown struct {
+				Content string `json:"content"`
+				Text    string `json:"text"`
+			} `json:"markdown"`
+			Card struct {
+				Elements []struct {
+					Content string `json:"content"`
+				} `json:"elements"`
+			} `json:"card"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("%s: invalid json: %v", provider, err)
+		}
+		switch provider {
+		case chat.ProviderWeCom:
+			return payload.Markdown.Content
+		case chat.ProviderDingTalk:
+			return payload.Markdown.Text
+		default:
+			return payload.Card.Elements[0].Content
+		}
+	}
+
+	for _, provider := range []chat.Provider{chat.ProviderWeCom, chat.ProviderDingTalk, chat.ProviderFeishu} {
+		_, body, err := Build(provider, "https://robot.example.com/send", "", card, time.Now())
+		if err != nil {
+			t.Fatalf("%s: Build: %v", provider, err)
+		}
+		text := markdownOf(provider, body)
+		if strings.Contains(text, "](https://evil.example)") {
+			t.Errorf("%s: title rendered as a link: %s", provider, text)
+		}
+		if strings.Contains(text, "<at") {
+			t.Errorf("%s: field rendered an <at> tag: %s", provider, text)
+		}
+		if strings.Contains(text, "@所有人") {
+			t.Errorf("%s: mention not neutralised: %s", provider, text)
+		}
+		if provider != chat.ProviderFeishu && !strings.Contains(text, "[查看任务]("+card.Link+")") {
+			t.Errorf("%s: task link missing: %s", provider, text)
+		}
+	}
+}
@@ -11,10 +11,19 @@ import (
 
 // Config 描述了后端服务所需的全部配置。
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
-	Auth   AuthConfig
-	Campus CampusAuthConfig
+	Server   ServerConfig
+	DB       DBConfig
+	Auth     AuthConfig
+	Campus   CampusAuthConfig
+	Deadline DeadlineConfig
+	Claim    ClaimPolicyConfig
+	Schedule ScheduleConfig
+	Storage  StorageConfig
+	Events   EventsConfig
+	Webhook  WebhookConfig
+	Outbox   OutboxConfig
+	Mail     MailConfig
+	Chat     ChatConfig
 }
 
 // ServerConfig 控制 HTTP 服务以及中间件参数。
@@ -61,6 +70,119 @@ type CampusAuthConfig struct {
 	Timeout   time.Duration
 }
 
+// DeadlineConfig 控制截止时间巡检与逾期领取的自动释放。
+type DeadlineConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	// AutoRelease 为 true 时释放逾期超过 ReleaseGrace 的领取，默认关闭，需运维显式开启。
+	AutoRelease  bool
+	ReleaseGrace time.Duration
+	// RemindBefore 为截止提醒的提前量，0 表示不提醒。
+	RemindBefore time.Duration
+}
+
+// ClaimPolicyConfig 控制成员领取任务的限制，MaxActive 为 0 表示不限制。
+type ClaimPolicyConfig struct {
+	MaxActive       int
+	ReleaseCooldown time.Duration
+}
+
+// ScheduleConfig 控制周期任务调度器。
+type ScheduleConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// 附件存储后端。
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// StorageConfig 控制附件存储后端与上传限制。
+type StorageConfig struct {
+	Driver         string
+	LocalDir       string
+	MaxUploadBytes int64
+	S3             S3Config
+}
+
+// S3Config 描述 S3 兼容对象存储的连接参数。
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Region    string
+	UseSSL    bool
+	PathStyle bool
+}
+
+// EventsConfig 控制 SSE 实时事件推送。
+type EventsConfig struct {
+	Heartbeat time.Duration
+	Backlog   int
+	// PGNotify 为 true 时经 PostgreSQL LISTEN/NOTIFY 在多个副本之间同步事件。
+	PGNotify bool
+}
+
+// WebhookConfig 控制外部 webhook 的投递与重试。
+type WebhookConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Timeout  time.Duration
+	// MaxAttempts 为单条投递的最大尝试次数，用尽后标记为失败。
+	MaxAttempts int
+	// RetryBase 为首次重试的等待时间，之后每次翻倍，不超过 RetryMax。
+	RetryBase time.Duration
+	RetryMax  time.Duration
+}
+
+// OutboxConfig 控制事务性发件箱的分发节奏。
+type OutboxConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// SMTP 连接加密方式。
+const (
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+	SMTPTLSNone     = "none"
+)
+
+// MailConfig 控制邮件通知的 SMTP 发送与每日汇总。
+type MailConfig struct {
+	Enabled  bool
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLS 取值 starttls、tls（隐式 TLS，通常为 465 端口）或 none（仅用于本地调试）。
+	TLS      string
+	From     string
+	Timeout  time.Duration
+	Interval time.Duration
+	// DigestHour 为每日汇总的发送时刻（服务器本地时间，0-23）。
+	DigestHour int
+	// LinkBaseURL 为前端地址，用于在邮件中生成任务链接，留空则不附链接。
+	LinkBaseURL string
+}
+
+// ChatConfig 控制群机器人推送与重试。
+type ChatConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Timeout  time.Duration
+	// MaxAttempts 为单条推送的最大尝试次数，用尽后标记为失败。
+	MaxAttempts int
+	// RetryBase 为首次重试的等待时间，之后每次翻倍，不超过 RetryMax。
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// LinkBaseURL 为前端地址，用于在卡片中生成任务链接，未设置时沿用 MAIL_LINK_BASE_URL。
+	LinkBaseURL string
+}
+
 // Load 从环境变量构建配置，未设置的值使用默认值。
 func Load() (Config, error) {
 	cfg := Config{
@@ -100,6 +222,74 @@ func Load() (Config, error) {
 			Remember:  lookupString("CAMPUS_AUTH_REMEMBER", "0"),
 			Timeout:   lookupDuration("CAMPUS_AUTH_TIMEOUT", 10*time.Second),
 		},
+		Deadline: DeadlineConfig{
+			Enabled:      lookupBool("DEADLINE_WORKER_ENABLED", true),
+			Interval:     lookupDuration("DEADLINE_CHECK_INTERVAL", time.Minute),
+			AutoRelease:  lookupBool("DEADLINE_AUTO_RELEASE", false),
+			ReleaseGrace: lookupDuration("DEADLINE_RELEASE_GRACE", 24*time.Hour),
+			RemindBefore: lookupDuration("DEADLINE_REMIND_BEFORE", 24*time.Hour),
+		},
+		Claim: ClaimPolicyConfig{
+			MaxActive:       lookupInt("CLAIM_MAX_ACTIVE", 3),
+			ReleaseCooldown: lookupDuration("CLAIM_RELEASE_COOLDOWN", 30*time.Minute),
+		},
+		Schedule: ScheduleConfig{
+			Enabled:  lookupBool("SCHEDULER_ENABLED", true),
+			Interval: lookupDuration("SCHEDULER_INTERVAL", 30*time.Second),
+		},
+		Storage: StorageConfig{
+			Driver:         strings.ToLower(lookupString("STORAGE_DRIVER", StorageDriverLocal)),
+			LocalDir:       lookupString("STORAGE_LOCAL_DIR", "./data/attachments"),
+			MaxUploadBytes: int64(lookupInt("ATTACHMENT_MAX_BYTES", 10<<20)),
+			S3: S3Config{
+				Endpoint:  lookupString("S3_ENDPOINT", ""),
+				Bucket:    lookupString("S3_BUCKET", ""),
+				AccessKey: lookupString("S3_ACCESS_KEY", ""),
+				SecretKey: lookupString("S3_SECRET_KEY", ""),
+				Region:    lookupString("S3_REGION", ""),
+				UseSSL:    lookupBool("S3_USE_SSL", true),
+				PathStyle: lookupBool("S3_PATH_STYLE", false),
+			},
+		},
+		Events: EventsConfig{
+			Heartbeat: lookupDuration("EVENTS_HEARTBEAT", 25*time.Second),
+			Backlog:   lookupInt("EVENTS_BACKLOG", 256),
+			PGNotify:  lookupBool("EVENTS_PG_NOTIFY", false),
+		},
+		Webhook: WebhookConfig{
+			Enabled:     lookupBool("WEBHOOK_WORKER_ENABLED", true),
+			Interval:    lookupDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			Timeout:     lookupDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts: lookupInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBase:   lookupDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+			RetryMax:    lookupDuration("WEBHOOK_RETRY_MAX", 6*time.Hour),
+		},
+		Outbox: OutboxConfig{
+			Interval:  lookupDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize: lookupInt("OUTBOX_BATCH_SIZE", 100),
+		},
+		Mail: MailConfig{
+			Enabled:     lookupBool("MAIL_ENABLED", false),
+			Host:        lookupString("SMTP_HOST", ""),
+			Port:        lookupInt("SMTP_PORT", 587),
+			Username:    lookupString("SMTP_USERNAME", ""),
+			Password:    lookupString("SMTP_PASSWORD", ""),
+			TLS:         strings.ToLower(lookupString("SMTP_TLS", SMTPTLSStartTLS)),
+			From:        lookupString("MAIL_FROM", ""),
+			Timeout:     lookupDuration("SMTP_TIMEOUT", 10*time.Second),
+			Interval:    lookupDuration("MAIL_POLL_INTERVAL", time.Minute),
+			DigestHour:  lookupInt("MAIL_DIGEST_HOUR", 8),
+			LinkBaseURL: strings.TrimRight(lookupString("MAIL_LINK_BASE_URL", ""), "/"),
+		},
+		Chat: ChatConfig{
+			Enabled:     lookupBool("CHAT_WORKER_ENABLED", true),
+			Interval:    lookupDuration("CHAT_POLL_INTERVAL", 5*time.Second),
+			Timeout:     lookupDuration("CHAT_TIMEOUT", 5*time.Second),
+			MaxAttempts: lookupInt("CHAT_MAX_ATTEMPTS", 5),
+			RetryBase:   lookupDuration("CHAT_RETRY_BASE", 30*time.Second),
+			RetryMax:    lookupDuration("CHAT_RETRY_MAX", 30*time.Minute),
+			LinkBaseURL: strings.TrimRight(lookupString("CHAT_LINK_BASE_URL", ""), "/"),
+		},
 	}
 
 	if !strings.HasPrefix(cfg.Server.Addr, ":") && !strings.Contains(cfg.Server.Addr, ":") {
@@ -130,6 +320,109 @@ func Load() (Config, error) {
 		cfg.Campus.Enabled = false
 	}
 
+	if cfg.Deadline.Interval <= 0 {
+		cfg.Deadline.Interval = time.Minute
+	}
+	if cfg.Deadline.ReleaseGrace < 0 {
+		cfg.Deadline.ReleaseGrace = 0
+	}
+	if cfg.Deadline.RemindBefore < 0 {
+		cfg.Deadline.RemindBefore = 0
+	}
+
+	if cfg.Schedule.Interval <= 0 {
+		cfg.Schedule.Interval = 30 * time.Second
+	}
+
+	switch cfg.Storage.Driver {
+	case StorageDriverLocal:
+	case StorageDriverS3:
+		if cfg.Storage.S3.Endpoint == "" || cfg.Storage.S3.Bucket == "" {
+			return Config{}, errors.New("STORAGE_DRIVER=s3 时必须配置 S3_ENDPOINT 与 S3_BUCKET")
+		}
+	default:
+		return Config{}, fmt.Errorf("STORAGE_DRIVER 不支持：%s", cfg.Storage.Driver)
+	}
+	if cfg.Storage.MaxUploadBytes <= 0 {
+		cfg.Storage.MaxUploadBytes = 10 << 20
+	}
+
+	if cfg.Events.Heartbeat <= 0 {
+		cfg.Events.Heartbeat = 25 * time.Second
+	}
+	if cfg.Events.Backlog < 0 {
+		cfg.Events.Backlog = 0
+	}
+
+	if cfg.Webhook.Interval <= 0 {
+		cfg.Webhook.Interval = 5 * time.Second
+	}
+	if cfg.Webhook.Timeout <= 0 {
+		cfg.Webhook.Timeout = 10 * time.Second
+	}
+	if cfg.Webhook.MaxAttempts <= 0 {
+		cfg.Webhook.MaxAttempts = 1
+	}
+	if cfg.Webhook.RetryBase <= 0 {
+		cfg.Webhook.RetryBase = 30 * time.Second
+	}
+	if cfg.Webhook.RetryMax < cfg.Webhook.RetryBase {
+		cfg.Webhook.RetryMax = cfg.Webhook.RetryBase
+	}
+
+	if cfg.Outbox.Interval <= 0 {
+		cfg.Outbox.Interval = time.Second
+	}
+	if cfg.Outbox.BatchSize <= 0 {
+		cfg.Outbox.BatchSize = 100
+	}
+
+	if cfg.Mail.Enabled {
+		if cfg.Mail.Host == "" || cfg.Mail.From == "" {
+			return Config{}, errors.New("MAIL_ENABLED=true 时必须配置 SMTP_HOST 与 MAIL_FROM")
+		}
+		switch cfg.Mail.TLS {
+		case SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone:
+		default:
+			return Config{}, fmt.Errorf("SMTP_TLS 不支持：%s", cfg.Mail.TLS)
+		}
+	}
+	if cfg.Mail.Timeout <= 0 {
+		cfg.Mail.Timeout = 10 * time.Second
+	}
+	if cfg.Mail.Interval <= 0 {
+		cfg.Mail.Interval = time.Minute
+	}
+	if cfg.Mail.DigestHour < 0 || cfg.Mail.DigestHour > 23 {
+		cfg.Mail.DigestHour = 8
+	}
+
+	if cfg.Chat.Interval <= 0 {
+		cfg.Chat.Interval = 5 * time.Second
+	}
+	if cfg.Chat.Timeout <= 0 {
+		cfg.Chat.Timeout = 5 * time.Second
+	}
+	if cfg.Chat.MaxAttempts <= 0 {
+		cfg.Chat.MaxAttempts = 1
+	}
+	if cfg.Chat.RetryBase <= 0 {
+		cfg.Chat.RetryBase = 30 * time.Second
+	}
+	if cfg.Chat.RetryMax < cfg.Chat.RetryBase {
+		cfg.Chat.RetryMax = cfg.Chat.RetryBase
+	}
+	if cfg.Chat.LinkBaseURL == "" {
+		cfg.Chat.LinkBaseURL = cfg.Mail.LinkBaseURL
+	}
+
+	if cfg.Claim.MaxActive < 0 {
+		cfg.Claim.MaxActive = 0
+	}
+	if cfg.Claim.ReleaseCooldown < 0 {
+		cfg.Claim.ReleaseCooldown = 0
+	}
+
 	return cfg, nil
 }
 
@@ -136,6 +136,360 @@ var migrationStatements = []string{
 	`ALTER TABLE tasks ADD CONSTRAINT chk_tasks_status CHECK (status IN ('draft','available','claimed','submitted','completed','archived'));`,
 	`ALTER TABLE task_assignments DROP CONSTRAINT IF EXISTS chk_task_assign_status;`,
 	`ALTER TABLE task_assignments ADD CONSTRAINT chk_task_assign_status CHECK (status IN ('claimed','submitted','completed','released'));`,
+
+	// 一次性数据迁移的执行记录，每项迁移写入一行，已存在则跳过
+	`CREATE TABLE IF NOT EXISTS schema_migrations (
+		name TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	// 积分流水表已存在说明此前的启动已补记过奖励，直接标记补记完成
+	`INSERT INTO schema_migrations (name)
+	SELECT 'point_ledger_backfill'
+	WHERE to_regclass('point_ledger') IS NOT NULL
+	ON CONFLICT (name) DO NOTHING;`,
+
+	// 积分流水
+	`CREATE TABLE IF NOT EXISTS point_ledger (
+		id BIGSERIAL PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind TEXT NOT NULL,
+		amount BIGINT NOT NULL,
+		task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+		assignment_id BIGINT REFERENCES task_assignments(id) ON DELETE SET NULL,
+		note TEXT,
+		created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT chk_point_ledger_kind CHECK (kind IN ('task_reward'))
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_point_ledger_user ON point_ledger (user_id, created_at DESC);`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_point_ledger_task_reward ON point_ledger (assignment_id) WHERE kind = 'task_reward';`,
+	// 为历史已完成的领取记录补记奖励流水，仅在首次创建积分流水表时执行一次：
+	// 之后的奖励只由验收写入，重复执行会按任务当前赏金为缺少流水的领取记录再次计奖
+	`WITH marker AS (
+		INSERT INTO schema_migrations (name) VALUES ('point_ledger_backfill')
+		ON CONFLICT (name) DO NOTHING
+		RETURNING name
+	)
+	INSERT INTO point_ledger (user_id, kind, amount, task_id, assignment_id, created_at)
+	SELECT ta.user_id, 'task_reward', t.bounty, t.id, ta.id, COALESCE(ta.completed_at, ta.created_at)
+	FROM task_assignments ta
+	JOIN tasks t ON t.id = ta.task_id
+	WHERE EXISTS (SELECT 1 FROM marker)
+		AND ta.status = 'completed'
+		AND t.bounty > 0
+		AND NOT EXISTS (
+			SELECT 1 FROM point_ledger pl
+			WHERE pl.assignment_id = ta.id AND pl.kind = 'task_reward'
+		);`,
+
+	// 积分人工调整（奖励、扣罚、撤销）
+	`CREATE TABLE IF NOT EXISTS point_adjustments (
+		id BIGSERIAL PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		operator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+		kind TEXT NOT NULL,
+		amount BIGINT NOT NULL,
+		task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+		reason TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT chk_point_adjustments_kind CHECK (kind IN ('bonus','penalty','reversal')),
+		CONSTRAINT chk_point_adjustments_reason CHECK (LENGTH(TRIM(reason)) > 0)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_point_adjustments_user ON point_adjustments (user_id, created_at DESC);`,
+	`ALTER TABLE point_ledger ADD COLUMN IF NOT EXISTS adjustment_id BIGINT REFERENCES point_adjustments(id) ON DELETE SET NULL;`,
+	`ALTER TABLE point_ledger DROP CONSTRAINT IF EXISTS chk_point_ledger_kind;`,
+	`ALTER TABLE point_ledger ADD CONSTRAINT chk_point_ledger_kind CHECK (kind IN ('task_reward','adjustment'));`,
+
+	// 审计日志按资源检索（任务时间线）
+	`CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs (resource, resource_id, created_at);`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action, id DESC);`,
+
+	// 提交验收记录（每轮提交说明与审核意见）
+	`CREATE TABLE IF NOT EXISTS task_submissions (
+		id BIGSERIAL PRIMARY KEY,
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		assignment_id BIGINT NOT NULL REFERENCES task_assignments(id) ON DELETE CASCADE,
+		round INTEGER NOT NULL,
+		submitted_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		note TEXT NOT NULL DEFAULT '',
+		links JSONB NOT NULL DEFAULT '[]'::jsonb,
+		submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		verdict TEXT NOT NULL DEFAULT 'pending',
+		reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+		review_comment TEXT,
+		reviewed_at TIMESTAMPTZ,
+		UNIQUE (task_id, round),
+		CONSTRAINT chk_task_submissions_verdict CHECK (verdict IN ('pending','approved','rejected'))
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_submissions_assignment ON task_submissions (assignment_id);`,
+
+	// 截止时间巡检：记录任务被标记为逾期的时间
+	`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMPTZ;`,
+
+	// 每个任务同一时间只允许一条进行中的领取，先释放历史遗留的重复领取（保留最新一条）
+	`UPDATE task_assignments ta
+	SET status = 'released', released_at = NOW()
+	WHERE ta.status IN ('claimed','submitted')
+		AND EXISTS (
+			SELECT 1 FROM task_assignments newer
+			WHERE newer.task_id = ta.task_id
+				AND newer.status IN ('claimed','submitted')
+				AND (newer.created_at, newer.id) > (ta.created_at, ta.id)
+		);`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_task_assignments_active ON task_assignments (task_id) WHERE status IN ('claimed','submitted');`,
+
+	// 任务模板（周期性运维事务）
+	`CREATE TABLE IF NOT EXISTS task_templates (
+		id UUID PRIMARY KEY,
+		title TEXT NOT NULL,
+		description_html TEXT NOT NULL,
+		bounty BIGINT NOT NULL DEFAULT 0,
+		priority TEXT NOT NULL DEFAULT 'medium',
+		tags JSONB NOT NULL DEFAULT '[]'::jsonb,
+		deadline_offset_minutes INTEGER NOT NULL DEFAULT 0,
+		created_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT chk_task_templates_priority CHECK (priority IN ('critical','high','medium','low')),
+		CONSTRAINT chk_task_templates_bounty CHECK (bounty >= 0),
+		CONSTRAINT chk_task_templates_offset CHECK (deadline_offset_minutes >= 0)
+	);`,
+
+	// 周期任务计划及每次触发记录，(schedule_id, occurrence) 唯一保证多副本不重复发布
+	`CREATE TABLE IF NOT EXISTS task_schedules (
+		id UUID PRIMARY KEY,
+		name TEXT NOT NULL,
+		cron_expr TEXT NOT NULL,
+		timezone TEXT NOT NULL DEFAULT 'UTC',
+		title TEXT NOT NULL,
+		description_html TEXT NOT NULL,
+		bounty BIGINT NOT NULL DEFAULT 0,
+		priority TEXT NOT NULL DEFAULT 'medium',
+		tags JSONB NOT NULL DEFAULT '[]'::jsonb,
+		deadline_offset_minutes INTEGER NOT NULL DEFAULT 0,
+		paused BOOLEAN NOT NULL DEFAULT FALSE,
+		next_run_at TIMESTAMPTZ,
+		last_run_at TIMESTAMPTZ,
+		created_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CONSTRAINT chk_task_schedules_priority CHECK (priority IN ('critical','high','medium','low')),
+		CONSTRAINT chk_task_schedules_bounty CHECK (bounty >= 0),
+		CONSTRAINT chk_task_schedules_offset CHECK (deadline_offset_minutes >= 0)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_schedules_due ON task_schedules (next_run_at) WHERE paused = FALSE;`,
+	`CREATE TABLE IF NOT EXISTS task_schedule_runs (
+		schedule_id UUID NOT NULL REFERENCES task_schedules(id) ON DELETE CASCADE,
+		occurrence TIMESTAMPTZ NOT NULL,
+		task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+		error TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (schedule_id, occurrence)
+	);`,
+
+	// 子任务：parent_id 指向父任务
+	`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE SET NULL;`,
+	`CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks (parent_id) WHERE parent_id IS NOT NULL;`,
+
+	// 任务依赖：task_id 被 blocked_by 阻塞，阻塞任务完成前不可领取
+	`CREATE TABLE IF NOT EXISTS task_dependencies (
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		blocked_by UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (task_id, blocked_by),
+		CHECK (task_id <> blocked_by)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by ON task_dependencies (blocked_by);`,
+
+	// 任务评论与 @ 提及
+	`CREATE TABLE IF NOT EXISTS task_comments (
+		id BIGSERIAL PRIMARY KEY,
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		author_id UUID NOT NULL REFERENCES users(id),
+		body_html TEXT NOT NULL,
+		body_plain TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		edited_at TIMESTAMPTZ,
+		deleted_at TIMESTAMPTZ,
+		deleted_by UUID REFERENCES users(id) ON DELETE SET NULL
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_comments_task ON task_comments (task_id, created_at) WHERE deleted_at IS NULL;`,
+	`CREATE TABLE IF NOT EXISTS task_comment_mentions (
+		comment_id BIGINT NOT NULL REFERENCES task_comments(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		PRIMARY KEY (comment_id, user_id)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_comment_mentions_user ON task_comment_mentions (user_id);`,
+
+	// 附件：内容按 sha256 去重存放在 BlobStore，同一任务（或同一轮提交）重复上传相同内容只保留一条记录
+	`CREATE TABLE IF NOT EXISTS attachments (
+		id UUID PRIMARY KEY,
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		submission_id BIGINT REFERENCES task_submissions(id) ON DELETE CASCADE,
+		filename TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size_bytes BIGINT NOT NULL,
+		sha256 TEXT NOT NULL,
+		uploaded_by UUID NOT NULL REFERENCES users(id),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMPTZ
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_attachments_task ON attachments (task_id, created_at) WHERE deleted_at IS NULL;`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_attachments_dedup ON attachments (task_id, COALESCE(submission_id, 0), sha256) WHERE deleted_at IS NULL;`,
+
+	// 检查清单：require_checklist 为 true 时须勾选全部条目才能提交验收
+	`CREATE TABLE IF NOT EXISTS task_checklist_items (
+		id BIGSERIAL PRIMARY KEY,
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		text TEXT NOT NULL,
+		done BOOLEAN NOT NULL DEFAULT FALSE,
+		done_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		done_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task ON task_checklist_items (task_id, position);`,
+	`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS require_checklist BOOLEAN NOT NULL DEFAULT FALSE;`,
+
+	// 任务关注者：创建者与领取者自动关注，通知按关注者扇出
+	`CREATE TABLE IF NOT EXISTS task_watchers (
+		task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (task_id, user_id)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_task_watchers_user ON task_watchers (user_id);`,
+
+	// 站内通知：dedup_key 保证截止提醒等巡检类通知对同一用户只发一次
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id BIGSERIAL PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind TEXT NOT NULL,
+		task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+		actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+		title TEXT NOT NULL,
+		body TEXT NOT NULL DEFAULT '',
+		dedup_key TEXT,
+		read_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);`,
+	`CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedup ON notifications (user_id, dedup_key) WHERE dedup_key IS NOT NULL;`,
+
+	// 外部 webhook：events 为空数组表示订阅全部事件，投递失败按指数退避重试并记录每次尝试
+	`CREATE TABLE IF NOT EXISTS webhooks (
+		id UUID PRIMARY KEY,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events JSONB NOT NULL DEFAULT '[]'::jsonb,
+		description TEXT NOT NULL DEFAULT '',
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		payload JSONB NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ,
+		last_status_code INTEGER,
+		last_error TEXT NOT NULL DEFAULT '',
+		redelivery_of BIGINT REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		delivered_at TIMESTAMPTZ
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);`,
+	`CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+		id BIGSERIAL PRIMARY KEY,
+		delivery_id BIGINT NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER,
+		error TEXT NOT NULL DEFAULT '',
+		response_body TEXT NOT NULL DEFAULT '',
+		duration_ms INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts (delivery_id, attempt);`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id) WHERE redelivery_of IS NULL;`,
+
+	// 事务性发件箱：任务变更在同一事务内写入事件，由分发器投递给 SSE 与 webhook，成功后删除
+	`CREATE TABLE IF NOT EXISTS outbox (
+		id BIGSERIAL PRIMARY KEY,
+		topic TEXT NOT NULL,
+		aggregate_id TEXT NOT NULL,
+		payload JSONB NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_outbox_available ON outbox (available_at, id);`,
+
+	// 邮件通知：按用户设置即时发送或每日汇总，emailed_at 标记已发送的站内通知；默认不发送，由用户自行开启
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_mode TEXT NOT NULL DEFAULT 'off' CHECK (email_mode IN ('off','instant','daily'));`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_digest_at TIMESTAMPTZ;`,
+	`ALTER TABLE notifications ADD COLUMN IF NOT EXISTS emailed_at TIMESTAMPTZ;`,
+	`ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_attempts INTEGER NOT NULL DEFAULT 0;`,
+	`CREATE INDEX IF NOT EXISTS idx_notifications_email_pending ON notifications (created_at) WHERE emailed_at IS NULL;`,
+
+	// 群机器人：按事件、标签与优先级过滤后向企业微信、钉钉或飞书群推送任务卡片
+	`CREATE TABLE IF NOT EXISTS chat_channels (
+		id UUID PRIMARY KEY,
+		name TEXT NOT NULL,
+		provider TEXT NOT NULL CHECK (provider IN ('wecom','dingtalk','feishu')),
+		webhook_url TEXT NOT NULL,
+		secret TEXT NOT NULL DEFAULT '',
+		events JSONB NOT NULL DEFAULT '[]'::jsonb,
+		tags JSONB NOT NULL DEFAULT '[]'::jsonb,
+		priorities JSONB NOT NULL DEFAULT '[]'::jsonb,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+
+	// 周期触发记录：pending 标记已认领但尚未发布完成的触发，超时后由下一轮巡检重新发布。
+	// 列默认值只作用于已有记录（均已处理完毕），新认领的触发显式写入 TRUE
+	`ALTER TABLE task_schedule_runs ADD COLUMN IF NOT EXISTS pending BOOLEAN NOT NULL DEFAULT FALSE;`,
+	`ALTER TABLE task_schedule_runs ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();`,
+	`CREATE INDEX IF NOT EXISTS idx_task_schedule_runs_pending ON task_schedule_runs (claimed_at) WHERE pending;`,
+
+	// 发件箱：delivered 记录已成功接收事件的分发目标，重试时只投递给失败的目标
+	`ALTER TABLE outbox ADD COLUMN IF NOT EXISTS delivered JSONB NOT NULL DEFAULT '[]'::jsonb;`,
+
+	// 邮件通知：新用户默认不发送邮件；email_locked_until 为即时邮件的发送租约，发送期间不持有行锁
+	`ALTER TABLE users ALTER COLUMN email_mode SET DEFAULT 'off';`,
+	`ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_locked_until TIMESTAMPTZ;`,
+
+	// 群机器人推送队列：每个频道每个事件一条，(channel_id, event_id) 唯一保证发件箱重试时不重复推送
+	`CREATE TABLE IF NOT EXISTS chat_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		channel_id UUID NOT NULL REFERENCES chat_channels(id) ON DELETE CASCADE,
+		event_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		card JSONB NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at TIMESTAMPTZ,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		delivered_at TIMESTAMPTZ,
+		UNIQUE (channel_id, event_id)
+	);`,
+	`CREATE INDEX IF NOT EXISTS idx_chat_deliveries_due ON chat_deliveries (next_attempt_at) WHERE status = 'pending';`,
+
+	// 每日汇总：发送失败的用户在 email_digest_retry_at 之前不再领取
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_digest_retry_at TIMESTAMPTZ;`,
 }
 
 // RunMigrations 会依次执行所有迁移语句，保证幂等。
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Action 审计动作。
+type Action string
+
+const (
+	ActionRoleGrant    Action = "role_grant"
+	ActionRoleRevoke   Action = "role_revoke"
+	ActionPointsAdjust Action = "points_adjust"
+
+	ActionTaskCreate   Action = "task_create"
+	ActionTaskUpdate   Action = "task_update"
+	ActionTaskDelete   Action = "task_delete"
+	ActionTaskPublish  Action = "task_publish"
+	ActionTaskArchive  Action = "task_archive"
+	ActionTaskStatus   Action = "task_status"
+	ActionTaskClaim    Action = "task_claim"
+	ActionTaskRelease  Action = "task_release"
+	ActionTaskSubmit   Action = "task_submit"
+	ActionTaskReject   Action = "task_reject"
+	ActionTaskComplete Action = "task_complete"
+	ActionTaskOverdue  Action = "task_overdue"
+
+	ActionTaskDependencyAdd    Action = "task_dependency_add"
+	ActionTaskDependencyRemove Action = "task_dependency_remove"
+
+	ActionCommentCreate Action = "comment_create"
+	ActionCommentUpdate Action = "comment_update"
+	ActionCommentDelete Action = "comment_delete"
+
+	ActionAttachmentUpload Action = "attachment_upload"
+	ActionAttachmentDelete Action = "attachment_delete"
+
+	ActionChecklistAdd     Action = "checklist_add"
+	ActionChecklistEdit    Action = "checklist_edit"
+	ActionChecklistRemove  Action = "checklist_remove"
+	ActionChecklistCheck   Action = "checklist_check"
+	ActionChecklistUncheck Action = "checklist_uncheck"
+
+	ActionTemplateCreate Action = "template_create"
+	ActionTemplateUpdate Action = "template_update"
+	ActionTemplateDelete Action = "template_delete"
+
+	ActionScheduleCreate Action = "schedule_create"
+	ActionScheduleUpdate Action = "schedule_update"
+	ActionScheduleDelete Action = "schedule_delete"
+	ActionSchedulePause  Action = "schedule_pause"
+	ActionScheduleResume Action = "schedule_resume"
+
+	ActionWebhookCreate    Action = "webhook_create"
+	ActionWebhookUpdate    Action = "webhook_update"
+	ActionWebhookDelete    Action = "webhook_delete"
+	ActionWebhookRedeliver Action = "webhook_redeliver"
+
+	ActionChatChannelCreate Action = "chat_channel_create"
+	ActionChatChannelUpdate Action = "chat_channel_update"
+	ActionChatChannelDelete Action = "chat_channel_delete"
+)
+
+// Log 描述一条审计日志记录。
+type Log struct {
+	ID         int64
+	ActorID    *uuid.UUID
+	ActorName  string
+	Action     Action
+	Resource   string
+	ResourceID string
+	Metadata   map[string]any
+	IP         string
+	UserAgent  string
+	CreatedAt  time.Time
+}
+
+// Client 记录发起操作的客户端信息。
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type contextKey struct{}
+
+// WithClient 将客户端信息写入 context，供仓储层写审计日志时读取。
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, client)
+}
+
+// ClientFromContext 读取 context 中的客户端信息。
+func ClientFromContext(ctx context.Context) Client {
+	if client, ok := ctx.Value(contextKey{}).(Client); ok {
+		return client
+	}
+	return Client{}
+}
@@ -0,0 +1,98 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+)
+
+// Provider 群机器人类型，决定消息格式与签名方式。
+type Provider string
+
+const (
+	ProviderWeCom    Provider = "wecom"
+	ProviderDingTalk Provider = "dingtalk"
+	ProviderFeishu   Provider = "feishu"
+)
+
+// Channel 是管理员配置的一个群机器人。Tags 与 Priorities 为空表示不按该条件过滤；
+// Secret 为钉钉、飞书的加签密钥，企业微信不使用。
+type Channel struct {
+	ID         uuid.UUID
+	Name       string
+	Provider   Provider
+	WebhookURL string
+	Secret     string
+	Events     []string
+	Tags       []string
+	Priorities []task.Priority
+	Active     bool
+	CreatedBy  uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Subscribes 判断频道是否订阅了该事件类型。
+func (c Channel) Subscribes(eventType string) bool {
+	for _, evt := range c.Events {
+		if evt == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches 判断任务是否满足频道的优先级与标签过滤条件，标签只需命中其一，不区分大小写。
+func (c Channel) Matches(priority task.Priority, tags []string) bool {
+	if len(c.Priorities) > 0 {
+		matched := false
+		for _, p := range c.Priorities {
+			if p == priority {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(c.Tags) == 0 {
+		return true
+	}
+	for _, want := range c.Tags {
+		for _, tag := range tags {
+			if strings.EqualFold(want, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DeliveryStatus 推送状态。
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery 是一个事件向某个频道的推送任务，Card 为入队时渲染好的卡片，失败后按退避间隔重试直到成功或次数用尽。
+type Delivery struct {
+	ID            int64
+	ChannelID     uuid.UUID
+	EventID       string
+	EventType     string
+	Card          json.RawMessage
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt *time.Time
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
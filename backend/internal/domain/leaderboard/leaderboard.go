@@ -0,0 +1,29 @@
+package leaderboard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Period 排行榜统计周期。
+type Period string
+
+const (
+	PeriodWeek     Period = "week"
+	PeriodMonth    Period = "month"
+	PeriodSemester Period = "semester"
+	PeriodAll      Period = "all"
+)
+
+// Standing 描述用户在某一周期内的排名。
+type Standing struct {
+	Rank            int
+	UserID          uuid.UUID
+	Username        string
+	DisplayName     string
+	Points          int64
+	TasksCompleted  int
+	LastCompletedAt time.Time
+	PreviousRank    *int
+}
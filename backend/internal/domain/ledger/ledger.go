@@ -0,0 +1,49 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind 积分流水类型。
+type Kind string
+
+// AdjustmentKind 人工调整类型。
+type AdjustmentKind string
+
+const (
+	KindTaskReward Kind = "task_reward"
+	KindAdjustment Kind = "adjustment"
+
+	AdjustmentBonus    AdjustmentKind = "bonus"
+	AdjustmentPenalty  AdjustmentKind = "penalty"
+	AdjustmentReversal AdjustmentKind = "reversal"
+)
+
+// Entry 描述一条积分流水。
+type Entry struct {
+	ID           int64
+	UserID       uuid.UUID
+	Kind         Kind
+	Amount       int64
+	TaskID       *uuid.UUID
+	TaskTitle    string
+	AssignmentID *int64
+	AdjustmentID *int64
+	Note         string
+	CreatedBy    *uuid.UUID
+	CreatedAt    time.Time
+}
+
+// Adjustment 记录管理员对积分的人工调整。
+type Adjustment struct {
+	ID         int64
+	UserID     uuid.UUID
+	OperatorID uuid.UUID
+	Kind       AdjustmentKind
+	Amount     int64
+	TaskID     *uuid.UUID
+	Reason     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind 站内通知类型。
+type Kind string
+
+const (
+	KindTaskClaimed      Kind = "task_claimed"
+	KindTaskSubmitted    Kind = "task_submitted"
+	KindTaskApproved     Kind = "task_approved"
+	KindTaskRejected     Kind = "task_rejected"
+	KindDeadlineApproach Kind = "deadline_approaching"
+	KindCommentMention   Kind = "comment_mention"
+)
+
+// Notification 是发给某个用户的一条站内通知，ReadAt 非空表示已读。
+type Notification struct {
+	ID        int64
+	UserID    uuid.UUID
+	Kind      Kind
+	TaskID    *uuid.UUID
+	TaskTitle string
+	ActorID   *uuid.UUID
+	ActorName string
+	Title     string
+	Body      string
+	// DedupKey 非空时同一用户只会收到一条相同键的通知，用于截止提醒等周期性巡检。
+	DedupKey  string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Message 是与业务变更在同一事务内写入的待分发事件，分发成功后删除。
+type Message struct {
+	ID          int64
+	Topic       string
+	AggregateID string
+	Payload     json.RawMessage
+	Attempts    int
+	// Delivered 为已成功接收该事件的分发目标名称，重试时跳过这些目标。
+	Delivered []string
+	CreatedAt time.Time
+}
+
+// Sink 接收分发的事件。分发器按目标记录投递结果，但进程在记录前退出时事件仍会再次投递，实现需能容忍重复。
+type Sink interface {
+	Handle(ctx context.Context, msg Message) error
+}
+
+// Target 是带名称的分发目标，名称写入事件的投递记录，需保持稳定。
+type Target struct {
+	Name string
+	Sink Sink
+}
+
+// SinkFunc 将普通函数适配为 Sink。
+type SinkFunc func(ctx context.Context, msg Message) error
+
+// Handle 调用 f 本身。
+func (f SinkFunc) Handle(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
@@ -0,0 +1,26 @@
+package task
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment 是挂在任务或某轮提交上的附件，内容按 SHA256 去重存放在 BlobStore 中。
+type Attachment struct {
+	ID           uuid.UUID
+	TaskID       uuid.UUID
+	SubmissionID *int64
+	Filename     string
+	ContentType  string
+	Size         int64
+	SHA256       string
+	UploadedBy   uuid.UUID
+	UploaderName string
+	CreatedAt    time.Time
+}
+
+// BlobKey 返回附件内容在存储后端中的 key，相同内容共享同一对象。
+func (a Attachment) BlobKey() string {
+	return "sha256/" + a.SHA256[:2] + "/" + a.SHA256
+}
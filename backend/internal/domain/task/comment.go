@@ -0,0 +1,28 @@
+package task
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment 是任务讨论区中的一条评论，DeletedAt 非空表示已被删除。
+type Comment struct {
+	ID         int64
+	TaskID     uuid.UUID
+	AuthorID   uuid.UUID
+	AuthorName string
+	BodyHTML   string
+	BodyPlain  string
+	Mentions   []Mention
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	EditedAt   *time.Time
+	DeletedAt  *time.Time
+}
+
+// Mention 记录评论中 @ 到的用户。
+type Mention struct {
+	UserID   uuid.UUID
+	Username string
+}
@@ -12,6 +12,9 @@ type Priority string
 // Status 任务状态枚举。
 type Status string
 
+// Verdict 验收结论。
+type Verdict string
+
 const (
 	PriorityCritical Priority = "critical"
 	PriorityHigh     Priority = "high"
@@ -24,6 +27,10 @@ const (
 	StatusSubmitted Status = "submitted"
 	StatusCompleted Status = "completed"
 	StatusArchived  Status = "archived"
+
+	VerdictPending  Verdict = "pending"
+	VerdictApproved Verdict = "approved"
+	VerdictRejected Verdict = "rejected"
 )
 
 // Task 描述任务主体。
@@ -41,8 +48,64 @@ type Task struct {
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	DeletedAt        *time.Time
+	OverdueAt        *time.Time
+	ParentID         *uuid.UUID
+	ChildCount       int
+	ChildBounty      int64
+	RequireChecklist bool
+	ChecklistTotal   int
+	ChecklistDone    int
+	Checklist        []ChecklistItem
+	Watchers         []uuid.UUID
+	BlockedBy        []Dependency
+	Blocks           []Dependency
 	Tags             []Tag
 	CurrentAssignee  *Assignment
+	Submissions      []Submission
+}
+
+// TotalBounty 返回任务自身赏金与全部子任务赏金之和。
+func (t Task) TotalBounty() int64 {
+	return t.Bounty + t.ChildBounty
+}
+
+// Blocked 表示任务仍有未完成的前置任务。
+func (t Task) Blocked() bool {
+	for _, dep := range t.BlockedBy {
+		if dep.Status != StatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecklistProgress 返回检查清单完成百分比，没有条目时为 0。
+func (t Task) ChecklistProgress() int {
+	if t.ChecklistTotal == 0 {
+		return 0
+	}
+	return t.ChecklistDone * 100 / t.ChecklistTotal
+}
+
+// ChecklistItem 是任务检查清单中的一步，按 Position 升序展示。
+type ChecklistItem struct {
+	ID         int64
+	TaskID     uuid.UUID
+	Position   int
+	Text       string
+	Done       bool
+	DoneBy     *uuid.UUID
+	DoneByName string
+	DoneAt     *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Dependency 描述依赖关系另一端的任务概要。
+type Dependency struct {
+	TaskID uuid.UUID
+	Title  string
+	Status Status
 }
 
 // Tag 为任务分类标签。
@@ -63,3 +126,67 @@ type Assignment struct {
 	CompletedAt *time.Time
 	ReleasedAt  *time.Time
 }
+
+// Submission 记录一轮提交验收及其审核结果。
+type Submission struct {
+	ID            int64
+	TaskID        uuid.UUID
+	AssignmentID  int64
+	Round         int
+	SubmittedBy   uuid.UUID
+	SubmitterName string
+	Note          string
+	Links         []string
+	SubmittedAt   time.Time
+	Verdict       Verdict
+	ReviewerID    *uuid.UUID
+	ReviewerName  string
+	ReviewComment string
+	ReviewedAt    *time.Time
+}
+
+// Activity 描述任务时间线中的一条事件，Kind 与审计动作保持一致。
+type Activity struct {
+	Kind         string
+	ActorID      *uuid.UUID
+	ActorName    string
+	AssigneeName string
+	FromStatus   Status
+	ToStatus     Status
+	Details      map[string]any
+	OccurredAt   time.Time
+}
+
+// Template 是可重复实例化的任务模板，DeadlineOffset 为实例化时间到截止时间的间隔，0 表示不设截止时间。
+type Template struct {
+	ID              uuid.UUID
+	Title           string
+	DescriptionHTML string
+	Bounty          int64
+	Priority        Priority
+	Tags            []string
+	DeadlineOffset  time.Duration
+	CreatedBy       uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Schedule 按 cron 表达式在指定时区周期性发布任务，任务内容取自其中保存的字段。
+type Schedule struct {
+	ID              uuid.UUID
+	Name            string
+	CronExpr        string
+	Timezone        string
+	Title           string
+	DescriptionHTML string
+	Bounty          int64
+	Priority        Priority
+	Tags            []string
+	DeadlineOffset  time.Duration
+	Paused          bool
+	NextRunAt       *time.Time
+	LastRunAt       *time.Time
+	CreatedBy       uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,151 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Event 触发任务状态变化的事件。
+type Event string
+
+const (
+	EventPublish   Event = "publish"
+	EventUnpublish Event = "unpublish"
+	EventArchive   Event = "archive"
+	EventDelete    Event = "delete"
+	EventClaim     Event = "claim"
+	EventRelease   Event = "release"
+	EventSubmit    Event = "submit"
+	EventReject    Event = "reject"
+	EventComplete  Event = "complete"
+)
+
+var (
+	// ErrInvalidTransition 表示状态机不允许该状态转换。
+	ErrInvalidTransition = errors.New("task: invalid status transition")
+	// ErrBlocked 表示任务仍有未完成的前置任务。
+	ErrBlocked = errors.New("task: blocked by unfinished dependencies")
+)
+
+// Facts 提供守卫条件判断所需的任务现状。
+type Facts struct {
+	// ActiveAssignment 表示任务存在已领取或待验收的领取记录。
+	ActiveAssignment bool
+	// OpenChildren 为尚未完成或归档的子任务数量。
+	OpenChildren int
+	// OpenBlockers 为尚未完成的前置任务数量。
+	OpenBlockers int
+	// RequireChecklist 表示提交前须勾选全部检查清单条目。
+	RequireChecklist bool
+	// OpenChecklistItems 为尚未勾选的检查清单条目数量。
+	OpenChecklistItems int
+}
+
+// Guard 是转换的前置条件，返回非 nil 时拒绝转换。
+type Guard func(Facts) error
+
+// Transition 描述一条合法的状态转换。Manual 为 true 时允许管理员直接修改状态触发。
+type Transition struct {
+	From   Status
+	Event  Event
+	To     Status
+	Manual bool
+	Guard  Guard
+}
+
+// Transitions 列出全部合法的 (from, event, to) 组合，未列出的转换一律拒绝。
+var Transitions = []Transition{
+	{From: StatusDraft, Event: EventPublish, To: StatusAvailable, Manual: true},
+	{From: StatusArchived, Event: EventPublish, To: StatusAvailable, Manual: true, Guard: requireNoActiveAssignment},
+	{From: StatusAvailable, Event: EventUnpublish, To: StatusDraft, Manual: true, Guard: requireNoActiveAssignment},
+
+	{From: StatusDraft, Event: EventArchive, To: StatusArchived, Manual: true},
+	{From: StatusAvailable, Event: EventArchive, To: StatusArchived, Manual: true, Guard: requireNoActiveAssignment},
+	{From: StatusCompleted, Event: EventArchive, To: StatusArchived, Manual: true},
+
+	{From: StatusAvailable, Event: EventClaim, To: StatusClaimed, Guard: all(requireNoActiveAssignment, requireUnblocked)},
+	{From: StatusClaimed, Event: EventRelease, To: StatusAvailable, Guard: requireActiveAssignment},
+	{From: StatusClaimed, Event: EventSubmit, To: StatusSubmitted, Guard: all(requireActiveAssignment, requireChecklistDone)},
+	{From: StatusSubmitted, Event: EventReject, To: StatusClaimed, Guard: requireActiveAssignment},
+	{From: StatusSubmitted, Event: EventComplete, To: StatusCompleted, Guard: all(requireActiveAssignment, requireChildrenClosed)},
+
+	{From: StatusDraft, Event: EventDelete, To: StatusArchived},
+	{From: StatusAvailable, Event: EventDelete, To: StatusArchived},
+	{From: StatusClaimed, Event: EventDelete, To: StatusArchived},
+	{From: StatusSubmitted, Event: EventDelete, To: StatusArchived},
+	{From: StatusCompleted, Event: EventDelete, To: StatusArchived},
+	{From: StatusArchived, Event: EventDelete, To: StatusArchived},
+}
+
+// Fire 校验 event 能否从 from 触发并返回目标状态。
+func Fire(from Status, event Event, facts Facts) (Status, error) {
+	for _, tr := range Transitions {
+		if tr.From != from || tr.Event != event {
+			continue
+		}
+		if tr.Guard != nil {
+			if err := tr.Guard(facts); err != nil {
+				return from, fmt.Errorf("%w: cannot %s %s task: %w", ErrInvalidTransition, event, from, err)
+			}
+		}
+		return tr.To, nil
+	}
+	return from, fmt.Errorf("%w: cannot %s %s task", ErrInvalidTransition, event, from)
+}
+
+// Resolve 查找从 from 直接设置为 to 时对应的事件，仅限 Manual 转换。
+func Resolve(from, to Status) (Event, error) {
+	for _, tr := range Transitions {
+		if tr.Manual && tr.From == from && tr.To == to {
+			return tr.Event, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+func requireNoActiveAssignment(f Facts) error {
+	if f.ActiveAssignment {
+		return errors.New("task has an active assignment")
+	}
+	return nil
+}
+
+func requireActiveAssignment(f Facts) error {
+	if !f.ActiveAssignment {
+		return errors.New("task has no active assignment")
+	}
+	return nil
+}
+
+func requireChildrenClosed(f Facts) error {
+	if f.OpenChildren > 0 {
+		return fmt.Errorf("%d subtasks are not completed or archived", f.OpenChildren)
+	}
+	return nil
+}
+
+func requireUnblocked(f Facts) error {
+	if f.OpenBlockers > 0 {
+		return fmt.Errorf("%w (%d open)", ErrBlocked, f.OpenBlockers)
+	}
+	return nil
+}
+
+func requireChecklistDone(f Facts) error {
+	if f.RequireChecklist && f.OpenChecklistItems > 0 {
+		return fmt.Errorf("%d checklist items are not checked", f.OpenChecklistItems)
+	}
+	return nil
+}
+
+// all 组合多个守卫，按顺序返回第一个失败原因。
+func all(guards ...Guard) Guard {
+	return func(f Facts) error {
+		for _, guard := range guards {
+			if err := guard(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
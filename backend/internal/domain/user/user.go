@@ -14,12 +14,22 @@ const (
 	RoleAdmin  Role = "admin"
 )
 
+// EmailMode 控制邮件通知的发送方式。
+type EmailMode string
+
+const (
+	EmailModeOff     EmailMode = "off"
+	EmailModeInstant EmailMode = "instant"
+	EmailModeDaily   EmailMode = "daily"
+)
+
 // User 描述用户主信息。
 type User struct {
 	ID          uuid.UUID
 	Username    string
 	DisplayName string
 	Email       string
+	EmailMode   EmailMode
 	Headline    string
 	Bio         string
 	AvatarURL   string
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 签名相关请求头，接收方按 Signature 校验请求体并可用 Timestamp 拒绝过旧的重放请求。
+const (
+	HeaderEvent     = "X-OpsBoard-Event"
+	HeaderDelivery  = "X-OpsBoard-Delivery"
+	HeaderTimestamp = "X-OpsBoard-Timestamp"
+	HeaderSignature = "X-OpsBoard-Signature"
+)
+
+// DeliveryStatus 投递状态。
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Webhook 是管理员配置的外部回调地址，Events 为空表示订阅全部事件。
+type Webhook struct {
+	ID          uuid.UUID
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+	Active      bool
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Subscribes 判断 webhook 是否订阅了该事件类型。
+func (w Webhook) Subscribes(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, evt := range w.Events {
+		if evt == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery 是一个事件对某个 webhook 的投递任务，失败后按退避间隔重试直到成功或次数用尽。
+type Delivery struct {
+	ID             int64
+	WebhookID      uuid.UUID
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      string
+	// RedeliveryOf 非空表示由管理员手动重新投递产生。
+	RedeliveryOf *int64
+	CreatedAt    time.Time
+	DeliveredAt  *time.Time
+	// History 仅在查询单条投递详情时加载。
+	History []Attempt
+}
+
+// Attempt 记录一次 HTTP 投递尝试的结果。
+type Attempt struct {
+	ID           int64
+	DeliveryID   int64
+	Attempt      int
+	StatusCode   *int
+	Error        string
+	ResponseBody string
+	Duration     time.Duration
+	CreatedAt    time.Time
+}
+
+// Sign 计算请求签名：HMAC-SHA256(secret, "<timestamp>.<body>")，返回 "sha256=<hex>" 形式。
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，供接收方或联调时使用。
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
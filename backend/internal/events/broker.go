@@ -0,0 +1,204 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/domain/outbox"
+)
+
+// 任务看板事件类型。
+const (
+	TypeTaskCreated   = "task.created"
+	TypeTaskUpdated   = "task.updated"
+	TypeTaskPublished = "task.published"
+	TypeTaskClaimed   = "task.claimed"
+	TypeTaskSubmitted = "task.submitted"
+	TypeTaskRejected  = "task.rejected"
+	TypeTaskCompleted = "task.completed"
+)
+
+// subscriberBuffer 为单个连接的待发送事件上限，写满说明客户端过慢，直接断开让其续传。
+const subscriberBuffer = 64
+
+// Event 是推送给客户端的一条实时事件，ID 全局唯一，用于 Last-Event-ID 续传。
+type Event struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+	At   time.Time       `json:"at"`
+}
+
+// Broker 在进程内向已连接的客户端扇出事件，并保留最近的事件供断线续传。
+// 启用 PGNotify 后事件先经 NOTIFY 广播，再由各副本的监听连接分发给本地客户端。
+type Broker struct {
+	cfg      config.EventsConfig
+	notifier *pgNotifier
+	log      *zap.Logger
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	backlog []Event
+	closed  bool
+	seq     atomic.Uint64
+}
+
+// Subscription 表示一个已连接的客户端。
+type Subscription struct {
+	ch   chan Event
+	once sync.Once
+}
+
+// NewBroker 构造事件中心，db 与 dsn 仅在启用 PGNotify 时使用：前者发送 NOTIFY，后者建立独立的监听连接。
+func NewBroker(cfg config.EventsConfig, db *sql.DB, dsn string, log *zap.Logger) *Broker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	b := &Broker{cfg: cfg, log: log, subs: make(map[*Subscription]struct{})}
+	if cfg.PGNotify {
+		b.notifier = newPGNotifier(db, dsn, b.dispatch, log)
+	}
+	return b
+}
+
+// Heartbeat 返回 SSE 心跳间隔。
+func (b *Broker) Heartbeat() time.Duration {
+	return b.cfg.Heartbeat
+}
+
+// Start 启动跨副本监听，未启用 PGNotify 时为空操作。
+func (b *Broker) Start(ctx context.Context) {
+	if b == nil || b.notifier == nil {
+		return
+	}
+	b.notifier.start(ctx)
+}
+
+// Stop 停止跨副本监听并断开全部客户端。
+func (b *Broker) Stop(ctx context.Context) {
+	if b == nil {
+		return
+	}
+	b.CloseSubscribers()
+	if b.notifier != nil {
+		if err := b.notifier.stop(ctx); err != nil {
+			b.log.Warn("event listener stop timed out", zap.Error(err))
+		}
+	}
+}
+
+// CloseSubscribers 断开全部客户端且不再接受新连接，供 HTTP 服务优雅关闭时调用。
+func (b *Broker) CloseSubscribers() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		sub.close()
+	}
+}
+
+// Publish 发布事件，data 会被序列化为 JSON。b 为 nil 时为空操作，方便业务层在未启用推送时直接调用。
+func (b *Broker) Publish(ctx context.Context, eventType string, data any) {
+	if b == nil {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		b.log.Warn("marshal event failed", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+
+	evt := Event{ID: b.nextID(), Type: eventType, Data: payload, At: time.Now().UTC()}
+	if b.notifier != nil && b.notifier.publish(ctx, evt) {
+		return
+	}
+	// 未启用或监听连接暂不可用时直接本地分发，至少保证本副本的客户端能收到。
+	b.dispatch(evt)
+}
+
+// Handle 实现 outbox.Sink，将发件箱中的任务事件推送给客户端。
+func (b *Broker) Handle(ctx context.Context, msg outbox.Message) error {
+	b.Publish(ctx, msg.Topic, msg.Payload)
+	return nil
+}
+
+// Subscribe 注册新客户端并返回 lastID 之后的积压事件。
+// lastID 非空但已不在积压窗口内时 resumed 为 false，客户端应全量刷新。
+func (b *Broker) Subscribe(lastID string) (sub *Subscription, replay []Event, resumed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{ch: make(chan Event, subscriberBuffer)}
+	if b.closed {
+		sub.close()
+		return sub, nil, false
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == "" {
+		return sub, nil, true
+	}
+	for i := len(b.backlog) - 1; i >= 0; i-- {
+		if b.backlog[i].ID == lastID {
+			replay = append(replay, b.backlog[i+1:]...)
+			return sub, replay, true
+		}
+	}
+	return sub, nil, false
+}
+
+// Unsubscribe 注销客户端，可重复调用。
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subs, sub)
+	b.mu.Unlock()
+	sub.close()
+}
+
+// Events 返回待发送事件，通道关闭表示连接应当结束。
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+func (b *Broker) dispatch(evt Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cfg.Backlog > 0 {
+		b.backlog = append(b.backlog, evt)
+		if over := len(b.backlog) - b.cfg.Backlog; over > 0 {
+			b.backlog = append(b.backlog[:0:0], b.backlog[over:]...)
+		}
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- evt:
+		default:
+			// 客户端消费过慢，断开后由浏览器携带 Last-Event-ID 重连续传。
+			delete(b.subs, sub)
+			sub.close()
+		}
+	}
+}
+
+// nextID 生成跨副本唯一的事件 ID：前缀区分副本，序号保证同一副本内递增。
+func (b *Broker) nextID() string {
+	return instanceID + "-" + strconv.FormatUint(b.seq.Add(1), 10)
+}
+
+var instanceID = uuid.NewString()[:8]
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// notifyChannel 为各副本共同监听的 PostgreSQL 通知频道。
+const notifyChannel = "opsboard_events"
+
+// maxNotifyPayload 略小于 PostgreSQL NOTIFY 8000 字节的上限，超出的事件只在本地分发。
+const maxNotifyPayload = 7900
+
+// pgNotifier 经 LISTEN/NOTIFY 在副本之间同步事件，监听连接断开后按退避间隔重连。
+type pgNotifier struct {
+	db      *sql.DB
+	dsn     string
+	deliver func(Event)
+	log     *zap.Logger
+	online  atomic.Bool
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func newPGNotifier(db *sql.DB, dsn string, deliver func(Event), log *zap.Logger) *pgNotifier {
+	return &pgNotifier{db: db, dsn: dsn, deliver: deliver, log: log}
+}
+
+// publish 通过 NOTIFY 广播事件，监听连接离线或发送失败时返回 false 由调用方本地分发。
+func (n *pgNotifier) publish(ctx context.Context, evt Event) bool {
+	if !n.online.Load() {
+		return false
+	}
+	payload, err := json.Marshal(evt)
+	if err != nil || len(payload) > maxNotifyPayload {
+		return false
+	}
+	if _, err := n.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, notifyChannel, string(payload)); err != nil {
+		n.log.Warn("pg_notify failed", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func (n *pgNotifier) start(ctx context.Context) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.cancel != nil {
+		return
+	}
+
+	ctx, n.cancel = context.WithCancel(ctx)
+	done := make(chan struct{})
+	n.done = done
+
+	go func() {
+		defer close(done)
+		backoff := time.Second
+		for {
+			err := n.listen(ctx)
+			if n.online.Swap(false) {
+				backoff = time.Second
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			n.log.Warn("event listener disconnected", zap.Error(err), zap.Duration("retry_in", backoff))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+		}
+	}()
+}
+
+func (n *pgNotifier) stop(ctx context.Context) error {
+	n.mu.Lock()
+	cancel, done := n.cancel, n.done
+	n.cancel, n.done = nil, nil
+	n.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *pgNotifier) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, n.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return err
+	}
+	n.online.Store(true)
+	n.log.Info("event listener connected", zap.String("channel", notifyChannel))
+
+	for {
+		msg, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var evt Event
+		if err := json.Unmarshal([]byte(msg.Payload), &evt); err != nil {
+			n.log.Warn("drop malformed event notification", zap.Error(err))
+			continue
+		}
+		n.deliver(evt)
+	}
+}
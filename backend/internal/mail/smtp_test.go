@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	from := &netmail.Address{Name: "OpsBoard", Address: "noreply@ops.example.com"}
+	to := &netmail.Address{Name: "张三", Address: "zhangsan@example.com"}
+	now := time.Date(2024, 3, 5, 9, 30, 0, 0, time.FixedZone("CST", 8*3600))
+	msg := Message{
+		To:      to.Address,
+		ToName:  to.Name,
+		Subject: "[OpsBoard] 任务已被领取",
+		Text:    "第一行\n第二行 = 等号\r\n" + strings.Repeat("长", 60),
+	}
+
+	raw, err := buildMessage(from, to, msg, now)
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	head, _, _ := bytes.Cut(raw, []byte("\r\n\r\n"))
+	for _, line := range strings.Split(string(head), "\r\n") {
+		if strings.ContainsAny(line, "\r\n") {
+			t.Fatalf("header line contains bare newline: %q", line)
+		}
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	h := parsed.Header
+
+	rawSubject := h.Get("Subject")
+	if !strings.HasPrefix(rawSubject, "=?utf-8?q?") {
+		t.Fatalf("subject not Q-encoded: %q", rawSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	if subject != msg.Subject {
+		t.Fatalf("subject = %q, want %q", subject, msg.Subject)
+	}
+
+	gotFrom, err := h.AddressList("From")
+	if err != nil || len(gotFrom) != 1 || gotFrom[0].Address != from.Address || gotFrom[0].Name != from.Name {
+		t.Fatalf("From = %v, %v", gotFrom, err)
+	}
+	gotTo, err := h.AddressList("To")
+	if err != nil || len(gotTo) != 1 || gotTo[0].Address != to.Address || gotTo[0].Name != to.Name {
+		t.Fatalf("To = %v, %v", gotTo, err)
+	}
+	date, err := h.Date()
+	if err != nil || !date.Equal(now) {
+		t.Fatalf("Date = %v, %v; want %v", date, err, now)
+	}
+	if id := h.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@ops.example.com>") {
+		t.Fatalf("Message-ID = %q", id)
+	}
+	for key, want := range map[string]string{
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding": "quoted-printable",
+	} {
+		if got := h.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	want := "第一行\r\n第二行 = 等号\r\n" + strings.Repeat("长", 60)
+	if string(body) != want {
+		t.Fatalf("body = %q, want %q", body, want)
+	}
+}
+
+func TestBuildMessageEncodesHeaderInjection(t *testing.T) {
+	from := &netmail.Address{Address: "noreply@ops.example.com"}
+	to := &netmail.Address{Address: "user@example.com"}
+	raw, err := buildMessage(from, to, Message{Subject: "hi\r\nBcc: victim@example.com", Text: "x"}, time.Now())
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	parsed, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if got := parsed.Header.Get("Bcc"); got != "" {
+		t.Fatalf("subject injected a Bcc header: %q", got)
+	}
+}
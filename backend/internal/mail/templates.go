@@ -0,0 +1,80 @@
+package mail
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationEmail 为单条通知邮件的模板数据。
+type NotificationEmail struct {
+	RecipientName string
+	Title         string
+	Body          string
+	TaskTitle     string
+	ActorName     string
+	Link          string
+	CreatedAt     time.Time
+}
+
+// DigestEmail 为每日汇总邮件的模板数据，Omitted 为超出单封上限未列出的条数。
+type DigestEmail struct {
+	RecipientName string
+	Date          string
+	Items         []NotificationEmail
+	Omitted       int
+}
+
+const footer = `
+—— OpsBoard 任务看板
+如不想再收到此类邮件，可在个人设置中关闭邮件通知或改为每日汇总。
+`
+
+var notificationTemplate = template.Must(template.New("notification").Parse(`{{.RecipientName}}，你好：
+
+{{.Title}}
+{{- if .Body}}
+
+{{.Body}}
+{{- end}}
+{{if .ActorName}}
+操作人：{{.ActorName}}
+{{- end}}
+{{- if .Link}}
+查看任务：{{.Link}}
+{{- end}}
+` + footer))
+
+var digestTemplate = template.Must(template.New("digest").Parse(`{{.RecipientName}}，你好：
+
+以下是自上次汇总以来你尚未阅读的通知：
+{{range .Items}}
+- {{.Title}}（{{.CreatedAt.Format "01-02 15:04"}}）
+{{- if .Body}}
+  {{.Body}}
+{{- end}}
+{{- if .Link}}
+  {{.Link}}
+{{- end}}
+{{end}}
+{{- if .Omitted}}
+另有 {{.Omitted}} 条通知未列出，请登录看板查看。
+{{end}}` + footer))
+
+// RenderNotification 渲染单条通知邮件。
+func RenderNotification(data NotificationEmail) (subject, text string, err error) {
+	var buf strings.Builder
+	if err := notificationTemplate.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return "[OpsBoard] " + data.Title, buf.String(), nil
+}
+
+// RenderDigest 渲染每日汇总邮件。
+func RenderDigest(data DigestEmail) (subject, text string, err error) {
+	var buf strings.Builder
+	if err := digestTemplate.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return "[OpsBoard] 每日通知汇总（" + data.Date + "）", buf.String(), nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// AttachmentRepository 定义附件元数据相关数据库操作。
+type AttachmentRepository interface {
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]task.Attachment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (task.Attachment, error)
+	Create(ctx context.Context, att task.Attachment) (task.Attachment, error)
+	Delete(ctx context.Context, id, actor uuid.UUID) error
+}
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository 构造附件仓储。
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentSelect = `
+SELECT a.id, a.task_id, a.submission_id, a.filename, a.content_type, a.size_bytes, a.sha256, a.uploaded_by, COALESCE(u.display_name, ''), a.created_at
+FROM attachments a
+LEFT JOIN users u ON u.id = a.uploaded_by
+`
+
+func (r *attachmentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]task.Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, attachmentSelect+`
+WHERE a.task_id = $1 AND a.deleted_at IS NULL
+ORDER BY a.created_at ASC
+`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]task.Attachment, 0)
+	for rows.Next() {
+		att, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, att)
+	}
+	return items, rows.Err()
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (task.Attachment, error) {
+	att, err := scanAttachment(r.db.QueryRowContext(ctx, attachmentSelect+`WHERE a.id = $1 AND a.deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Attachment{}, ErrNotFound
+	}
+	return att, err
+}
+
+// Create 保存附件元数据；同一任务（或同一轮提交）已有相同内容时直接返回已有记录。
+func (r *attachmentRepository) Create(ctx context.Context, att task.Attachment) (task.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	defer tx.Rollback()
+
+	if att.SubmissionID != nil {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM task_submissions WHERE id = $1 AND task_id = $2)
+`, *att.SubmissionID, att.TaskID).Scan(&exists); err != nil {
+			return task.Attachment{}, err
+		}
+		if !exists {
+			return task.Attachment{}, ErrNotFound
+		}
+	}
+
+	now := time.Now().UTC()
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+INSERT INTO attachments (id, task_id, submission_id, filename, content_type, size_bytes, sha256, uploaded_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (task_id, COALESCE(submission_id, 0), sha256) WHERE deleted_at IS NULL DO NOTHING
+RETURNING id
+`, uuid.New(), att.TaskID, att.SubmissionID, att.Filename, att.ContentType, att.Size, att.SHA256, att.UploadedBy, now).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		existing, err := scanAttachment(tx.QueryRowContext(ctx, attachmentSelect+`
+WHERE a.task_id = $1 AND COALESCE(a.submission_id, 0) = COALESCE($2, 0) AND a.sha256 = $3 AND a.deleted_at IS NULL
+`, att.TaskID, att.SubmissionID, att.SHA256))
+		if err != nil {
+			return task.Attachment{}, err
+		}
+		return existing, tx.Commit()
+	}
+	if err != nil {
+		return task.Attachment{}, err
+	}
+
+	meta := map[string]any{
+		"attachmentId": id.String(),
+		"filename":     att.Filename,
+		"size":         att.Size,
+	}
+	if att.SubmissionID != nil {
+		meta["submissionId"] = *att.SubmissionID
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    att.UploadedBy,
+		Action:     audit.ActionAttachmentUpload,
+		Resource:   "task",
+		ResourceID: att.TaskID.String(),
+		Metadata:   meta,
+		CreatedAt:  now,
+	}); err != nil {
+		return task.Attachment{}, err
+	}
+
+	created, err := scanAttachment(tx.QueryRowContext(ctx, attachmentSelect+`WHERE a.id = $1`, id))
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Attachment{}, err
+	}
+	return created, nil
+}
+
+// Delete 软删除附件记录，存储中的内容可能被其他附件共享，因此保留。
+func (r *attachmentRepository) Delete(ctx context.Context, id, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var (
+		taskID   uuid.UUID
+		filename string
+	)
+	err = tx.QueryRowContext(ctx, `
+UPDATE attachments SET deleted_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING task_id, filename
+`, id, now).Scan(&taskID, &filename)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionAttachmentDelete,
+		Resource:   "task",
+		ResourceID: taskID.String(),
+		Metadata: map[string]any{
+			"attachmentId": id.String(),
+			"filename":     filename,
+		},
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanAttachment(row rowScanner) (task.Attachment, error) {
+	var (
+		att          task.Attachment
+		submissionID sql.NullInt64
+	)
+	if err := row.Scan(
+		&att.ID,
+		&att.TaskID,
+		&submissionID,
+		&att.Filename,
+		&att.ContentType,
+		&att.Size,
+		&att.SHA256,
+		&att.UploadedBy,
+		&att.UploaderName,
+		&att.CreatedAt,
+	); err != nil {
+		return task.Attachment{}, err
+	}
+	if submissionID.Valid {
+		id := submissionID.Int64
+		att.SubmissionID = &id
+	}
+	return att, nil
+}
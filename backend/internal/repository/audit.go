@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// auditEntry 描述一条待写入的审计日志。
+type auditEntry struct {
+	ActorID    uuid.UUID
+	Action     audit.Action
+	Resource   string
+	ResourceID string
+	Metadata   map[string]any
+	CreatedAt  time.Time
+}
+
+// insertAuditLogTx 在调用方事务内写入审计日志，客户端 IP 与 User-Agent 取自 context。
+func insertAuditLogTx(ctx context.Context, tx *sql.Tx, entry auditEntry) error {
+	meta := entry.Metadata
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	metaRaw, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	var actor any
+	if entry.ActorID != uuid.Nil {
+		actor = entry.ActorID
+	}
+
+	client := audit.ClientFromContext(ctx)
+	var ip any
+	if parsed := net.ParseIP(strings.TrimSpace(client.IP)); parsed != nil {
+		ip = parsed.String()
+	}
+	var userAgent any
+	if ua := strings.TrimSpace(client.UserAgent); ua != "" {
+		userAgent = ua
+	}
+
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+
+	const query = `
+INSERT INTO audit_logs (user_id, action, resource, resource_id, metadata, ip_address, user_agent, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+	_, err = tx.ExecContext(ctx, query,
+		actor,
+		string(entry.Action),
+		entry.Resource,
+		entry.ResourceID,
+		string(metaRaw),
+		ip,
+		userAgent,
+		createdAt,
+	)
+	return err
+}
+
+// insertTaskAuditTx 记录任务变更，metadata 中包含状态流转与字段差异；同时写入发件箱，保证事件与变更一同提交。
+func insertTaskAuditTx(ctx context.Context, tx *sql.Tx, actor uuid.UUID, action audit.Action, taskID uuid.UUID, from, to task.Status, changes map[string]any, extra map[string]any) error {
+	meta := map[string]any{
+		"fromStatus": string(from),
+		"toStatus":   string(to),
+	}
+	if from != to {
+		if changes == nil {
+			changes = map[string]any{}
+		}
+		changes["status"] = fieldChange(string(from), string(to))
+	}
+	if len(changes) > 0 {
+		meta["changes"] = changes
+	}
+	for k, v := range extra {
+		meta[k] = v
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     action,
+		Resource:   "task",
+		ResourceID: taskID.String(),
+		Metadata:   meta,
+	}); err != nil {
+		return err
+	}
+	return insertTaskEventTx(ctx, tx, actor, action, taskID, extra)
+}
+
+func fieldChange(from, to any) map[string]any {
+	return map[string]any{"from": from, "to": to}
+}
+
+// diffTask 对比任务更新前后的可编辑字段，返回发生变化的字段。
+func diffTask(before, after task.Task) map[string]any {
+	changes := make(map[string]any)
+	if before.Title != after.Title {
+		changes["title"] = fieldChange(before.Title, after.Title)
+	}
+	if before.DescriptionHTML != after.DescriptionHTML {
+		changes["descriptionHtml"] = fieldChange(before.DescriptionHTML, after.DescriptionHTML)
+	}
+	if before.Bounty != after.Bounty {
+		changes["bounty"] = fieldChange(before.Bounty, after.Bounty)
+	}
+	if before.Priority != after.Priority {
+		changes["priority"] = fieldChange(string(before.Priority), string(after.Priority))
+	}
+	if !sameDeadline(before.Deadline, after.Deadline) {
+		changes["deadline"] = fieldChange(formatDeadline(before.Deadline), formatDeadline(after.Deadline))
+	}
+	if before.RequireChecklist != after.RequireChecklist {
+		changes["requireChecklist"] = fieldChange(before.RequireChecklist, after.RequireChecklist)
+	}
+	beforeTags, afterTags := tagNames(before.Tags), tagNames(after.Tags)
+	if strings.Join(beforeTags, "\x00") != strings.Join(afterTags, "\x00") {
+		changes["tags"] = fieldChange(beforeTags, afterTags)
+	}
+	return changes
+}
+
+func sameDeadline(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func formatDeadline(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func tagNames(tags []task.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tagItem := range tags {
+		names = append(names, tagItem.Name)
+	}
+	return names
+}
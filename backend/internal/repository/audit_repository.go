@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+)
+
+// AuditFilter 控制审计日志查询条件，BeforeID 用于按 id 倒序的游标分页。
+type AuditFilter struct {
+	ActorID    uuid.UUID
+	Actions    []audit.Action
+	Resource   string
+	ResourceID string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+// AuditRepository 定义审计日志的读取操作。
+type AuditRepository interface {
+	List(ctx context.Context, filter AuditFilter) ([]audit.Log, error)
+	Stream(ctx context.Context, filter AuditFilter, fn func(audit.Log) error) error
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository 构造审计日志仓储。
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter) ([]audit.Log, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	filter.Limit = limit
+
+	logs := make([]audit.Log, 0, limit)
+	err := r.query(ctx, filter, func(item audit.Log) error {
+		logs = append(logs, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// Stream 逐行回调查询结果，不做条数限制，适用于导出。
+func (r *auditRepository) Stream(ctx context.Context, filter AuditFilter, fn func(audit.Log) error) error {
+	filter.Limit = 0
+	return r.query(ctx, filter, fn)
+}
+
+func (r *auditRepository) query(ctx context.Context, filter AuditFilter, fn func(audit.Log) error) error {
+	args := make([]any, 0)
+	conditions := make([]string, 0)
+
+	if filter.ActorID != uuid.Nil {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("al.user_id = $%d", len(args)))
+	}
+	if len(filter.Actions) > 0 {
+		placeholders := make([]string, 0, len(filter.Actions))
+		for _, action := range filter.Actions {
+			args = append(args, string(action))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		conditions = append(conditions, fmt.Sprintf("al.action IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	if resource := strings.TrimSpace(filter.Resource); resource != "" {
+		args = append(args, resource)
+		conditions = append(conditions, fmt.Sprintf("al.resource = $%d", len(args)))
+	}
+	if resourceID := strings.TrimSpace(filter.ResourceID); resourceID != "" {
+		args = append(args, resourceID)
+		conditions = append(conditions, fmt.Sprintf("al.resource_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("al.created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("al.created_at < $%d", len(args)))
+	}
+	if filter.BeforeID > 0 {
+		args = append(args, filter.BeforeID)
+		conditions = append(conditions, fmt.Sprintf("al.id < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limitClause := ""
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		limitClause = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+SELECT
+	al.id,
+	al.user_id,
+	COALESCE(u.display_name, ''),
+	al.action,
+	COALESCE(al.resource, ''),
+	COALESCE(al.resource_id, ''),
+	al.metadata,
+	COALESCE(HOST(al.ip_address), ''),
+	COALESCE(al.user_agent, ''),
+	al.created_at
+FROM audit_logs al
+LEFT JOIN users u ON u.id = al.user_id
+%s
+ORDER BY al.id DESC
+%s
+`, where, limitClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item    audit.Log
+			actorID sql.NullString
+			metaRaw []byte
+		)
+		if err := rows.Scan(
+			&item.ID,
+			&actorID,
+			&item.ActorName,
+			&item.Action,
+			&item.Resource,
+			&item.ResourceID,
+			&metaRaw,
+			&item.IP,
+			&item.UserAgent,
+			&item.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if actorID.Valid {
+			if id, err := uuid.Parse(actorID.String); err == nil {
+				item.ActorID = &id
+			}
+		}
+		if len(metaRaw) > 0 {
+			if err := json.Unmarshal(metaRaw, &item.Metadata); err != nil {
+				return fmt.Errorf("decode audit metadata: %w", err)
+			}
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/chat"
+	"backend/internal/domain/task"
+)
+
+// ChatChannelRepository 定义群机器人频道相关数据库操作。
+type ChatChannelRepository interface {
+	List(ctx context.Context) ([]chat.Channel, error)
+	ListActive(ctx context.Context, eventType string) ([]chat.Channel, error)
+	GetByID(ctx context.Context, id uuid.UUID) (chat.Channel, error)
+	Create(ctx context.Context, channel chat.Channel) (chat.Channel, error)
+	Update(ctx context.Context, channel chat.Channel, actor uuid.UUID) (chat.Channel, error)
+	Delete(ctx context.Context, id, actor uuid.UUID) error
+	EnqueueDeliveries(ctx context.Context, channelIDs []uuid.UUID, eventID, eventType string, card []byte, at time.Time) (int, error)
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]DueChatDelivery, error)
+	RecordAttempt(ctx context.Context, input ChatAttemptInput) error
+}
+
+type chatChannelRepository struct {
+	db *sql.DB
+}
+
+// NewChatChannelRepository 构造群机器人频道仓储。
+func NewChatChannelRepository(db *sql.DB) ChatChannelRepository {
+	return &chatChannelRepository{db: db}
+}
+
+const chatChannelColumns = `id, name, provider, webhook_url, secret, events, tags, priorities, active, created_by, created_at, updated_at`
+
+func (r *chatChannelRepository) List(ctx context.Context) ([]chat.Channel, error) {
+	return r.query(ctx, `SELECT `+chatChannelColumns+` FROM chat_channels ORDER BY created_at ASC`)
+}
+
+// ListActive 返回订阅了该事件类型的启用中频道。
+func (r *chatChannelRepository) ListActive(ctx context.Context, eventType string) ([]chat.Channel, error) {
+	return r.query(ctx, `
+SELECT `+chatChannelColumns+`
+FROM chat_channels
+WHERE active AND events ? $1
+ORDER BY created_at ASC
+`, eventType)
+}
+
+func (r *chatChannelRepository) query(ctx context.Context, query string, args ...any) ([]chat.Channel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]chat.Channel, 0)
+	for rows.Next() {
+		channel, err := scanChatChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+func (r *chatChannelRepository) GetByID(ctx context.Context, id uuid.UUID) (chat.Channel, error) {
+	channel, err := scanChatChannel(r.db.QueryRowContext(ctx, `SELECT `+chatChannelColumns+` FROM chat_channels WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return chat.Channel{}, ErrNotFound
+	}
+	return channel, err
+}
+
+func (r *chatChannelRepository) Create(ctx context.Context, channel chat.Channel) (chat.Channel, error) {
+	eventsRaw, tagsRaw, prioritiesRaw, err := marshalChatFilters(channel)
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return chat.Channel{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	created, err := scanChatChannel(tx.QueryRowContext(ctx, `
+INSERT INTO chat_channels (id, name, provider, webhook_url, secret, events, tags, priorities, active, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $11)
+RETURNING `+chatChannelColumns,
+		uuid.New(),
+		channel.Name,
+		string(channel.Provider),
+		channel.WebhookURL,
+		channel.Secret,
+		eventsRaw,
+		tagsRaw,
+		prioritiesRaw,
+		channel.Active,
+		channel.CreatedBy,
+		now,
+	))
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    channel.CreatedBy,
+		Action:     audit.ActionChatChannelCreate,
+		Resource:   "chat_channel",
+		ResourceID: created.ID.String(),
+		Metadata: map[string]any{
+			"name":       created.Name,
+			"provider":   string(created.Provider),
+			"events":     created.Events,
+			"tags":       created.Tags,
+			"priorities": created.Priorities,
+			"active":     created.Active,
+		},
+		CreatedAt: now,
+	}); err != nil {
+		return chat.Channel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return chat.Channel{}, err
+	}
+	return created, nil
+}
+
+func (r *chatChannelRepository) Update(ctx context.Context, channel chat.Channel, actor uuid.UUID) (chat.Channel, error) {
+	eventsRaw, tagsRaw, prioritiesRaw, err := marshalChatFilters(channel)
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return chat.Channel{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanChatChannel(tx.QueryRowContext(ctx, `SELECT `+chatChannelColumns+` FROM chat_channels WHERE id = $1 FOR UPDATE`, channel.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return chat.Channel{}, ErrNotFound
+	}
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	now := time.Now().UTC()
+	updated, err := scanChatChannel(tx.QueryRowContext(ctx, `
+UPDATE chat_channels
+SET name = $2,
+	provider = $3,
+	webhook_url = $4,
+	secret = $5,
+	events = $6,
+	tags = $7,
+	priorities = $8,
+	active = $9,
+	updated_at = $10
+WHERE id = $1
+RETURNING `+chatChannelColumns,
+		channel.ID,
+		channel.Name,
+		string(channel.Provider),
+		channel.WebhookURL,
+		channel.Secret,
+		eventsRaw,
+		tagsRaw,
+		prioritiesRaw,
+		channel.Active,
+		now,
+	))
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	// 机器人地址中带有访问令牌，与密钥一样只记录是否变更。
+	changes := make(map[string]any)
+	if before.Name != updated.Name {
+		changes["name"] = fieldChange(before.Name, updated.Name)
+	}
+	if before.Provider != updated.Provider {
+		changes["provider"] = fieldChange(string(before.Provider), string(updated.Provider))
+	}
+	if !slices.Equal(before.Events, updated.Events) {
+		changes["events"] = fieldChange(before.Events, updated.Events)
+	}
+	if !slices.Equal(before.Tags, updated.Tags) {
+		changes["tags"] = fieldChange(before.Tags, updated.Tags)
+	}
+	if !slices.Equal(before.Priorities, updated.Priorities) {
+		changes["priorities"] = fieldChange(before.Priorities, updated.Priorities)
+	}
+	if before.Active != updated.Active {
+		changes["active"] = fieldChange(before.Active, updated.Active)
+	}
+	meta := map[string]any{"name": updated.Name, "changes": changes}
+	if before.WebhookURL != updated.WebhookURL {
+		meta["webhookUrlChanged"] = true
+	}
+	if before.Secret != updated.Secret {
+		meta["secretChanged"] = true
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionChatChannelUpdate,
+		Resource:   "chat_channel",
+		ResourceID: updated.ID.String(),
+		Metadata:   meta,
+		CreatedAt:  now,
+	}); err != nil {
+		return chat.Channel{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return chat.Channel{}, err
+	}
+	return updated, nil
+}
+
+func (r *chatChannelRepository) Delete(ctx context.Context, id, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var name, provider string
+	err = tx.QueryRowContext(ctx, `DELETE FROM chat_channels WHERE id = $1 RETURNING name, provider`, id).Scan(&name, &provider)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionChatChannelDelete,
+		Resource:   "chat_channel",
+		ResourceID: id.String(),
+		Metadata:   map[string]any{"name": name, "provider": provider},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func marshalChatFilters(channel chat.Channel) (events, tags, priorities string, err error) {
+	if events, err = marshalTags(channel.Events); err != nil {
+		return "", "", "", err
+	}
+	if tags, err = marshalTags(channel.Tags); err != nil {
+		return "", "", "", err
+	}
+	levels := make([]string, 0, len(channel.Priorities))
+	for _, p := range channel.Priorities {
+		levels = append(levels, string(p))
+	}
+	if priorities, err = marshalTags(levels); err != nil {
+		return "", "", "", err
+	}
+	return events, tags, priorities, nil
+}
+
+func scanChatChannel(row rowScanner) (chat.Channel, error) {
+	var (
+		channel       chat.Channel
+		provider      string
+		eventsRaw     []byte
+		tagsRaw       []byte
+		prioritiesRaw []byte
+		createdBy     uuid.NullUUID
+	)
+	if err := row.Scan(
+		&channel.ID,
+		&channel.Name,
+		&provider,
+		&channel.WebhookURL,
+		&channel.Secret,
+		&eventsRaw,
+		&tagsRaw,
+		&prioritiesRaw,
+		&channel.Active,
+		&createdBy,
+		&channel.CreatedAt,
+		&channel.UpdatedAt,
+	); err != nil {
+		return chat.Channel{}, err
+	}
+	channel.Provider = chat.Provider(provider)
+	channel.Events = make([]string, 0)
+	channel.Tags = make([]string, 0)
+	channel.Priorities = make([]task.Priority, 0)
+	for _, field := range []struct {
+		raw  []byte
+		dest any
+	}{
+		{eventsRaw, &channel.Events},
+		{tagsRaw, &channel.Tags},
+		{prioritiesRaw, &channel.Priorities},
+	} {
+		if len(field.raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(field.raw, field.dest); err != nil {
+			return chat.Channel{}, err
+		}
+	}
+	channel.CreatedBy = createdBy.UUID
+	return channel, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/chat"
+)
+
+// DueChatDelivery 是一条已被当前副本租用、待发送的推送，附带频道的机器人地址与加签密钥。
+type DueChatDelivery struct {
+	Delivery   chat.Delivery
+	Provider   chat.Provider
+	WebhookURL string
+	Secret     string
+}
+
+// ChatAttemptInput 描述一次推送尝试的结果，NextAttemptAt 仅在仍需重试时设置。
+type ChatAttemptInput struct {
+	DeliveryID    int64
+	Error         string
+	Status        chat.DeliveryStatus
+	NextAttemptAt *time.Time
+	At            time.Time
+}
+
+const chatDeliveryColumns = `d.id, d.channel_id, d.event_id, d.event_type, d.card, d.status, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at`
+
+// EnqueueDeliveries 为各频道写入一条待推送记录，同一频道的同一事件只会写入一次，返回新增数量。
+func (r *chatChannelRepository) EnqueueDeliveries(ctx context.Context, channelIDs []uuid.UUID, eventID, eventType string, card []byte, at time.Time) (int, error) {
+	if len(channelIDs) == 0 {
+		return 0, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	created := 0
+	for _, id := range channelIDs {
+		res, err := tx.ExecContext(ctx, `
+INSERT INTO chat_deliveries (channel_id, event_id, event_type, card, next_attempt_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (channel_id, event_id) DO NOTHING
+`, id, eventID, eventType, string(card), at)
+		if err != nil {
+			return 0, err
+		}
+		affected, _ := res.RowsAffected()
+		created += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// ClaimDueDeliveries 领取到期的推送并将 next_attempt_at 推迟到 leaseUntil 作为租约，多副本并发时借助 SKIP LOCKED 各取不同记录。
+// 已停用频道的推送保持待发送，重新启用后继续推送。
+func (r *chatChannelRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]DueChatDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+WITH due AS (
+	SELECT d.id
+	FROM chat_deliveries d
+	JOIN chat_channels c ON c.id = d.channel_id AND c.active
+	WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+	ORDER BY d.next_attempt_at ASC, d.id ASC
+	LIMIT $3
+	FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE chat_deliveries d
+SET next_attempt_at = $2
+FROM due, chat_channels c
+WHERE d.id = due.id AND c.id = d.channel_id
+RETURNING `+chatDeliveryColumns+`, c.provider, c.webhook_url, c.secret
+`, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]DueChatDelivery, 0)
+	for rows.Next() {
+		var (
+			item     DueChatDelivery
+			provider string
+		)
+		item.Delivery, err = scanChatDelivery(rows, &provider, &item.WebhookURL, &item.Secret)
+		if err != nil {
+			return nil, err
+		}
+		item.Provider = chat.Provider(provider)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// RecordAttempt 更新推送状态与尝试次数。
+func (r *chatChannelRepository) RecordAttempt(ctx context.Context, input ChatAttemptInput) error {
+	var deliveredAt *time.Time
+	if input.Status == chat.DeliverySucceeded {
+		deliveredAt = &input.At
+	}
+	res, err := r.db.ExecContext(ctx, `
+UPDATE chat_deliveries
+SET attempts = attempts + 1,
+	status = $2,
+	next_attempt_at = $3,
+	last_error = $4,
+	delivered_at = $5
+WHERE id = $1
+`, input.DeliveryID, string(input.Status), input.NextAttemptAt, input.Error, deliveredAt)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scanChatDelivery 扫描 chatDeliveryColumns，extra 用于接收查询末尾追加的列。
+func scanChatDelivery(row rowScanner, extra ...any) (chat.Delivery, error) {
+	var (
+		item          chat.Delivery
+		status        string
+		card          []byte
+		nextAttemptAt sql.NullTime
+		deliveredAt   sql.NullTime
+	)
+	dest := []any{
+		&item.ID,
+		&item.ChannelID,
+		&item.EventID,
+		&item.EventType,
+		&card,
+		&status,
+		&item.Attempts,
+		&nextAttemptAt,
+		&item.LastError,
+		&item.CreatedAt,
+		&deliveredAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return chat.Delivery{}, err
+	}
+	item.Status = chat.DeliveryStatus(status)
+	item.Card = card
+	if nextAttemptAt.Valid {
+		t := nextAttemptAt.Time
+		item.NextAttemptAt = &t
+	}
+	if deliveredAt.Valid {
+		t := deliveredAt.Time
+		item.DeliveredAt = &t
+	}
+	return item, nil
+}
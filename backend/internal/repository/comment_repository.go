@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// CommentRepository 定义任务评论相关数据库操作。
+type CommentRepository interface {
+	List(ctx context.Context, taskID uuid.UUID) ([]task.Comment, error)
+	GetByID(ctx context.Context, id int64) (task.Comment, error)
+	Create(ctx context.Context, comment task.Comment, mentions []string) (task.Comment, error)
+	Update(ctx context.Context, id int64, bodyHTML, bodyPlain string, mentions []string, actor uuid.UUID) (task.Comment, error)
+	Delete(ctx context.Context, id int64, actor uuid.UUID) error
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository 构造任务评论仓储。
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+const commentSelect = `
+SELECT c.id, c.task_id, c.author_id, COALESCE(u.display_name, ''), c.body_html, c.body_plain, c.created_at, c.updated_at, c.edited_at, c.deleted_at
+FROM task_comments c
+LEFT JOIN users u ON u.id = c.author_id
+`
+
+func (r *commentRepository) List(ctx context.Context, taskID uuid.UUID) ([]task.Comment, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)
+`, taskID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, commentSelect+`
+WHERE c.task_id = $1 AND c.deleted_at IS NULL
+ORDER BY c.created_at ASC, c.id ASC
+`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]task.Comment, 0)
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := attachMentions(ctx, r.db, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id int64) (task.Comment, error) {
+	return r.fetchComment(ctx, r.db, id)
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment task.Comment, mentions []string) (task.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Comment{}, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)
+`, comment.TaskID).Scan(&exists); err != nil {
+		return task.Comment{}, err
+	}
+	if !exists {
+		return task.Comment{}, ErrNotFound
+	}
+
+	now := time.Now().UTC()
+	var id int64
+	if err := tx.QueryRowContext(ctx, `
+INSERT INTO task_comments (task_id, author_id, body_html, body_plain, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $5)
+RETURNING id
+`, comment.TaskID, comment.AuthorID, comment.BodyHTML, comment.BodyPlain, now).Scan(&id); err != nil {
+		return task.Comment{}, err
+	}
+
+	resolved, err := replaceMentionsTx(ctx, tx, id, comment.AuthorID, mentions)
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    comment.AuthorID,
+		Action:     audit.ActionCommentCreate,
+		Resource:   "task",
+		ResourceID: comment.TaskID.String(),
+		Metadata:   commentMeta(id, comment.BodyPlain, resolved),
+		CreatedAt:  now,
+	}); err != nil {
+		return task.Comment{}, err
+	}
+
+	created, err := r.fetchComment(ctx, tx, id)
+	if err != nil {
+		return task.Comment{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Comment{}, err
+	}
+	return created, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, id int64, bodyHTML, bodyPlain string, mentions []string, actor uuid.UUID) (task.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Comment{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var (
+		taskID   uuid.UUID
+		authorID uuid.UUID
+	)
+	err = tx.QueryRowContext(ctx, `
+UPDATE task_comments
+SET body_html = $2,
+	body_plain = $3,
+	edited_at = $4,
+	updated_at = $4
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING task_id, author_id
+`, id, bodyHTML, bodyPlain, now).Scan(&taskID, &authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Comment{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	resolved, err := replaceMentionsTx(ctx, tx, id, authorID, mentions)
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionCommentUpdate,
+		Resource:   "task",
+		ResourceID: taskID.String(),
+		Metadata:   commentMeta(id, bodyPlain, resolved),
+		CreatedAt:  now,
+	}); err != nil {
+		return task.Comment{}, err
+	}
+
+	updated, err := r.fetchComment(ctx, tx, id)
+	if err != nil {
+		return task.Comment{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Comment{}, err
+	}
+	return updated, nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id int64, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var (
+		taskID   uuid.UUID
+		authorID uuid.UUID
+	)
+	err = tx.QueryRowContext(ctx, `
+UPDATE task_comments
+SET deleted_at = $2, deleted_by = $3, updated_at = $2
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING task_id, author_id
+`, id, now, actor).Scan(&taskID, &authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionCommentDelete,
+		Resource:   "task",
+		ResourceID: taskID.String(),
+		Metadata: map[string]any{
+			"commentId": id,
+			"authorId":  authorID.String(),
+			"moderated": authorID != actor,
+		},
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type rowQueryer interface {
+	queryer
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *commentRepository) fetchComment(ctx context.Context, q rowQueryer, id int64) (task.Comment, error) {
+	comment, err := scanComment(q.QueryRowContext(ctx, commentSelect+`WHERE c.id = $1 AND c.deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Comment{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	comments := []task.Comment{comment}
+	if err := attachMentions(ctx, q, comments); err != nil {
+		return task.Comment{}, err
+	}
+	return comments[0], nil
+}
+
+func scanComment(row rowScanner) (task.Comment, error) {
+	var (
+		comment   task.Comment
+		editedAt  sql.NullTime
+		deletedAt sql.NullTime
+	)
+	if err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.AuthorID,
+		&comment.AuthorName,
+		&comment.BodyHTML,
+		&comment.BodyPlain,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&editedAt,
+		&deletedAt,
+	); err != nil {
+		return task.Comment{}, err
+	}
+	if editedAt.Valid {
+		t := editedAt.Time
+		comment.EditedAt = &t
+	}
+	if deletedAt.Valid {
+		t := deletedAt.Time
+		comment.DeletedAt = &t
+	}
+	comment.Mentions = make([]task.Mention, 0)
+	return comment, nil
+}
+
+// replaceMentionsTx 按用户名（不区分大小写）解析提及对象并重写评论的提及记录，未知用户名与作者本人会被忽略。
+func replaceMentionsTx(ctx context.Context, tx *sql.Tx, commentID int64, authorID uuid.UUID, usernames []string) ([]task.Mention, error) {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_comment_mentions WHERE comment_id = $1`, commentID); err != nil {
+		return nil, err
+	}
+	resolved := make([]task.Mention, 0)
+	if len(usernames) == 0 {
+		return resolved, nil
+	}
+
+	lowered := make([]string, 0, len(usernames))
+	for _, name := range usernames {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+SELECT id, username FROM users
+WHERE LOWER(username) = ANY($1) AND status = 'active' AND id <> $2
+ORDER BY username ASC
+`, lowered, authorID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var mention task.Mention
+		if err := rows.Scan(&mention.UserID, &mention.Username); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		resolved = append(resolved, mention)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	for _, mention := range resolved {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO task_comment_mentions (comment_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`, commentID, mention.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+func attachMentions(ctx context.Context, q queryer, comments []task.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(comments))
+	index := make(map[int64]int, len(comments))
+	for i, comment := range comments {
+		ids = append(ids, comment.ID)
+		index[comment.ID] = i
+	}
+
+	rows, err := q.QueryContext(ctx, `
+SELECT m.comment_id, u.id, u.username
+FROM task_comment_mentions m
+JOIN users u ON u.id = m.user_id
+WHERE m.comment_id = ANY($1)
+ORDER BY u.username ASC
+`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			commentID int64
+			mention   task.Mention
+		)
+		if err := rows.Scan(&commentID, &mention.UserID, &mention.Username); err != nil {
+			return err
+		}
+		if idx, ok := index[commentID]; ok {
+			comments[idx].Mentions = append(comments[idx].Mentions, mention)
+		}
+	}
+	return rows.Err()
+}
+
+// commentMeta 生成评论审计元数据，摘要供任务时间线展示。
+func commentMeta(id int64, plain string, mentions []task.Mention) map[string]any {
+	excerpt := plain
+	if utf8.RuneCountInString(excerpt) > 140 {
+		excerpt = string([]rune(excerpt)[:140]) + "…"
+	}
+	names := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		names = append(names, mention.Username)
+	}
+	return map[string]any{
+		"commentId": id,
+		"excerpt":   excerpt,
+		"mentions":  names,
+	}
+}
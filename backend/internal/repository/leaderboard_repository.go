@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/leaderboard"
+)
+
+// LeaderboardFilter 控制排行榜统计范围。
+type LeaderboardFilter struct {
+	From  *time.Time
+	To    *time.Time
+	Tag   string
+	Limit int
+}
+
+// LeaderboardRepository 定义排行榜统计查询。
+type LeaderboardRepository interface {
+	Standings(ctx context.Context, filter LeaderboardFilter) ([]leaderboard.Standing, error)
+}
+
+type leaderboardRepository struct {
+	db *sql.DB
+}
+
+// NewLeaderboardRepository 构造排行榜仓储。
+func NewLeaderboardRepository(db *sql.DB) LeaderboardRepository {
+	return &leaderboardRepository{db: db}
+}
+
+// Standings 按周期内完成任务的赏金总和排序；同分时比较完成数，再以最后一次完成时间更早者优先。
+func (r *leaderboardRepository) Standings(ctx context.Context, filter LeaderboardFilter) ([]leaderboard.Standing, error) {
+	args := make([]any, 0)
+	conditions := []string{"ta.status = 'completed'", "ta.completed_at IS NOT NULL"}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("ta.completed_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("ta.completed_at < $%d", len(args)))
+	}
+	if tag := strings.TrimSpace(filter.Tag); tag != "" {
+		args = append(args, tag)
+		conditions = append(conditions, fmt.Sprintf(`
+EXISTS (
+	SELECT 1
+	FROM task_tag_map tm
+	JOIN task_tags tt ON tt.id = tm.tag_id
+	WHERE tm.task_id = t.id
+		AND LOWER(tt.name) = LOWER($%d)
+)`, len(args)))
+	}
+
+	limitClause := ""
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		limitClause = fmt.Sprintf("LIMIT $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+SELECT
+	ta.user_id,
+	u.username,
+	u.display_name,
+	COALESCE(SUM(t.bounty), 0) AS points,
+	COUNT(*) AS tasks_completed,
+	MAX(ta.completed_at) AS last_completed_at
+FROM task_assignments ta
+JOIN tasks t ON t.id = ta.task_id
+JOIN users u ON u.id = ta.user_id
+WHERE %s
+GROUP BY ta.user_id, u.username, u.display_name
+ORDER BY points DESC, tasks_completed DESC, last_completed_at ASC, ta.user_id ASC
+%s
+`, strings.Join(conditions, " AND "), limitClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	standings := make([]leaderboard.Standing, 0)
+	for rows.Next() {
+		var st leaderboard.Standing
+		if err := rows.Scan(
+			&st.UserID,
+			&st.Username,
+			&st.DisplayName,
+			&st.Points,
+			&st.TasksCompleted,
+			&st.LastCompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		st.Rank = len(standings) + 1
+		standings = append(standings, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return standings, nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/ledger"
+)
+
+// ErrNothingToReverse 表示该任务没有可撤销的奖励。
+var ErrNothingToReverse = errors.New("repository: nothing to reverse")
+
+// AdjustmentInput 描述一次人工积分调整。
+type AdjustmentInput struct {
+	UserID     uuid.UUID
+	OperatorID uuid.UUID
+	Kind       ledger.AdjustmentKind
+	Amount     int64
+	TaskID     *uuid.UUID
+	Reason     string
+}
+
+// LedgerRepository 定义积分流水相关数据库操作。
+type LedgerRepository interface {
+	Balance(ctx context.Context, userID uuid.UUID) (int64, error)
+	ListEntries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ledger.Entry, int, error)
+	Adjust(ctx context.Context, input AdjustmentInput) (ledger.Adjustment, error)
+}
+
+type ledgerRepository struct {
+	db *sql.DB
+}
+
+// NewLedgerRepository 构造积分流水仓储。
+func NewLedgerRepository(db *sql.DB) LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+func (r *ledgerRepository) Balance(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var balance int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM point_ledger WHERE user_id = $1`, userID).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (r *ledgerRepository) ListEntries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ledger.Entry, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	const query = `
+SELECT
+	pl.id,
+	pl.user_id,
+	pl.kind,
+	pl.amount,
+	pl.task_id,
+	COALESCE(t.title, ''),
+	pl.assignment_id,
+	pl.adjustment_id,
+	COALESCE(pl.note, ''),
+	pl.created_by,
+	pl.created_at
+FROM point_ledger pl
+LEFT JOIN tasks t ON t.id = pl.task_id
+WHERE pl.user_id = $1
+ORDER BY pl.created_at DESC, pl.id DESC
+LIMIT $2 OFFSET $3
+`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := make([]ledger.Entry, 0)
+	for rows.Next() {
+		var (
+			entry        ledger.Entry
+			taskID       sql.NullString
+			assignmentID sql.NullInt64
+			adjustmentID sql.NullInt64
+			createdBy    sql.NullString
+		)
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Kind,
+			&entry.Amount,
+			&taskID,
+			&entry.TaskTitle,
+			&assignmentID,
+			&adjustmentID,
+			&entry.Note,
+			&createdBy,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if taskID.Valid {
+			if id, err := uuid.Parse(taskID.String); err == nil {
+				entry.TaskID = &id
+			}
+		}
+		if assignmentID.Valid {
+			id := assignmentID.Int64
+			entry.AssignmentID = &id
+		}
+		if adjustmentID.Valid {
+			id := adjustmentID.Int64
+			entry.AdjustmentID = &id
+		}
+		if createdBy.Valid {
+			if id, err := uuid.Parse(createdBy.String); err == nil {
+				entry.CreatedBy = &id
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM point_ledger WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *ledgerRepository) Adjust(ctx context.Context, input AdjustmentInput) (ledger.Adjustment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ledger.Adjustment{}, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, input.UserID).Scan(&exists); err != nil {
+		return ledger.Adjustment{}, err
+	}
+	if !exists {
+		return ledger.Adjustment{}, ErrNotFound
+	}
+
+	amount := input.Amount
+	if input.Kind == ledger.AdjustmentReversal {
+		// 撤销金额取该任务已发放奖励扣除历次撤销后的净额，避免重复撤销。
+		// 先锁住对应的奖励流水，同一奖励的并发撤销依次执行，后到者看到的净额已扣除前一次撤销。
+		if _, err := tx.ExecContext(ctx, `
+SELECT id FROM point_ledger
+WHERE user_id = $1 AND task_id = $2 AND kind = 'task_reward'
+FOR UPDATE
+`, input.UserID, input.TaskID); err != nil {
+			return ledger.Adjustment{}, err
+		}
+		var net int64
+		if err := tx.QueryRowContext(ctx, `
+SELECT COALESCE(SUM(pl.amount), 0)
+FROM point_ledger pl
+LEFT JOIN point_adjustments pa ON pa.id = pl.adjustment_id
+WHERE pl.user_id = $1
+	AND pl.task_id = $2
+	AND (pl.kind = 'task_reward' OR pa.kind = 'reversal')
+`, input.UserID, input.TaskID).Scan(&net); err != nil {
+			return ledger.Adjustment{}, err
+		}
+		if net <= 0 {
+			return ledger.Adjustment{}, ErrNothingToReverse
+		}
+		amount = -net
+	}
+
+	now := time.Now().UTC()
+	adj := ledger.Adjustment{
+		UserID:     input.UserID,
+		OperatorID: input.OperatorID,
+		Kind:       input.Kind,
+		Amount:     amount,
+		TaskID:     input.TaskID,
+		Reason:     input.Reason,
+		CreatedAt:  now,
+	}
+	if err := tx.QueryRowContext(ctx, `
+INSERT INTO point_adjustments (user_id, operator_id, kind, amount, task_id, reason, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
+`, adj.UserID, adj.OperatorID, string(adj.Kind), adj.Amount, adj.TaskID, adj.Reason, now).Scan(&adj.ID); err != nil {
+		return ledger.Adjustment{}, err
+	}
+
+	operatorID := input.OperatorID
+	if err := insertLedgerEntryTx(ctx, tx, ledger.Entry{
+		UserID:       adj.UserID,
+		Kind:         ledger.KindAdjustment,
+		Amount:       adj.Amount,
+		TaskID:       adj.TaskID,
+		AdjustmentID: &adj.ID,
+		Note:         adj.Reason,
+		CreatedBy:    &operatorID,
+		CreatedAt:    now,
+	}); err != nil {
+		return ledger.Adjustment{}, err
+	}
+
+	meta := map[string]any{
+		"adjustmentId": adj.ID,
+		"kind":         adj.Kind,
+		"amount":       adj.Amount,
+		"reason":       adj.Reason,
+	}
+	if adj.TaskID != nil {
+		meta["taskId"] = adj.TaskID.String()
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    operatorID,
+		Action:     audit.ActionPointsAdjust,
+		Resource:   "user",
+		ResourceID: adj.UserID.String(),
+		Metadata:   meta,
+		CreatedAt:  now,
+	}); err != nil {
+		return ledger.Adjustment{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ledger.Adjustment{}, err
+	}
+	return adj, nil
+}
+
+// insertLedgerEntryTx 在调用方事务内写入积分流水，保证与业务变更同时提交。
+func insertLedgerEntryTx(ctx context.Context, tx *sql.Tx, entry ledger.Entry) error {
+	const query = `
+INSERT INTO point_ledger (user_id, kind, amount, task_id, assignment_id, adjustment_id, note, created_by, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+`
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	_, err := tx.ExecContext(ctx, query,
+		entry.UserID,
+		string(entry.Kind),
+		entry.Amount,
+		entry.TaskID,
+		entry.AssignmentID,
+		entry.AdjustmentID,
+		entry.Note,
+		entry.CreatedBy,
+		createdAt,
+	)
+	return err
+}
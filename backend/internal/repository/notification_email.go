@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/notification"
+)
+
+// maxEmailAttempts 为单条通知邮件的最大发送次数，超过后不再尝试。
+const maxEmailAttempts = 5
+
+// EmailRecipient 描述邮件通知的收件人。
+type EmailRecipient struct {
+	UserID      uuid.UUID
+	Email       string
+	DisplayName string
+}
+
+// DigestRecipient 为已领取本期汇总的收件人，PreviousDigestAt 为上一次汇总时间，发送失败时据此回退。
+type DigestRecipient struct {
+	EmailRecipient
+	PreviousDigestAt *time.Time
+}
+
+const emailNotificationColumns = `
+	n.id,
+	n.user_id,
+	n.kind,
+	n.task_id,
+	COALESCE(t.title, ''),
+	n.actor_id,
+	COALESCE(a.display_name, ''),
+	n.title,
+	n.body,
+	n.created_at`
+
+// DispatchInstantEmails 领取最多 limit 条待发送的即时邮件并逐条交给 send：成功的标记为已发送，失败的累加尝试次数。
+// 领取时写入 email_locked_until 作为租约并立即提交，发送在事务外进行，不会在 SMTP 请求期间持有行锁；
+// 进程在记录结果前退出时租约到期后重新发送。since 之前的通知以及用户已在站内读过的通知不再发送。
+func (r *notificationRepository) DispatchInstantEmails(ctx context.Context, since time.Time, limit int, lease time.Duration, send func(context.Context, EmailRecipient, notification.Notification) error) (int, error) {
+	batch, err := r.claimInstantEmails(ctx, since, limit, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, p := range batch {
+		if sendErr := send(ctx, p.recipient, p.item); sendErr != nil {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			if _, err := r.db.ExecContext(ctx, `
+UPDATE notifications SET email_attempts = email_attempts + 1, email_locked_until = NULL WHERE id = $1
+`, p.item.ID); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if _, err := r.db.ExecContext(ctx, `
+UPDATE notifications SET emailed_at = $2, email_locked_until = NULL WHERE id = $1
+`, p.item.ID, time.Now().UTC()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+type pendingEmail struct {
+	recipient EmailRecipient
+	item      notification.Notification
+}
+
+// claimInstantEmails 锁定一批待发送且未被租用的通知，写入租约后提交。
+func (r *notificationRepository) claimInstantEmails(ctx context.Context, since time.Time, limit int, lease time.Duration) ([]pendingEmail, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	rows, err := tx.QueryContext(ctx, `
+SELECT`+emailNotificationColumns+`,
+	u.email,
+	u.display_name
+FROM notifications n
+JOIN users u ON u.id = n.user_id
+LEFT JOIN tasks t ON t.id = n.task_id
+LEFT JOIN users a ON a.id = n.actor_id
+WHERE n.emailed_at IS NULL
+	AND n.read_at IS NULL
+	AND n.email_attempts < $2
+	AND n.created_at >= $1
+	AND (n.email_locked_until IS NULL OR n.email_locked_until <= $4)
+	AND u.email_mode = 'instant'
+	AND COALESCE(u.email, '') <> ''
+	AND u.status = 'active'
+ORDER BY n.id ASC
+LIMIT $3
+FOR UPDATE OF n SKIP LOCKED
+`, since, maxEmailAttempts, limit, now)
+	if err != nil {
+		return nil, err
+	}
+	batch := make([]pendingEmail, 0)
+	for rows.Next() {
+		var p pendingEmail
+		item, err := scanEmailNotification(rows, &p.recipient.Email, &p.recipient.DisplayName)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.item = item
+		p.recipient.UserID = item.UserID
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range batch {
+		if _, err := tx.ExecContext(ctx, `UPDATE notifications SET email_locked_until = $2 WHERE id = $1`, p.item.ID, now.Add(lease)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ClaimDigestRecipient 领取一位本期尚未发送汇总的每日汇总用户，并将其汇总时间更新为 now。
+// 多副本并发时借助 SKIP LOCKED 保证每位用户只被一个副本领取；发送失败后未到重试时间的用户不会被领取，
+// 避免一个无法送达的邮箱反复占用巡检。没有待发送用户时 ok 为 false。
+func (r *notificationRepository) ClaimDigestRecipient(ctx context.Context, periodStart, now time.Time) (DigestRecipient, bool, error) {
+	var (
+		recipient DigestRecipient
+		previous  sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx, `
+WITH due AS (
+	SELECT id, email_digest_at
+	FROM users
+	WHERE email_mode = 'daily'
+		AND COALESCE(email, '') <> ''
+		AND status = 'active'
+		AND (email_digest_at IS NULL OR email_digest_at < $1)
+		AND (email_digest_retry_at IS NULL OR email_digest_retry_at <= $2)
+	ORDER BY id
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE users u
+SET email_digest_at = $2,
+	email_digest_retry_at = NULL
+FROM due
+WHERE u.id = due.id
+RETURNING u.id, u.email, u.display_name, due.email_digest_at
+`, periodStart, now).Scan(&recipient.UserID, &recipient.Email, &recipient.DisplayName, &previous)
+	if err == sql.ErrNoRows {
+		return DigestRecipient{}, false, nil
+	}
+	if err != nil {
+		return DigestRecipient{}, false, err
+	}
+	if previous.Valid {
+		t := previous.Time
+		recipient.PreviousDigestAt = &t
+	}
+	return recipient, true, nil
+}
+
+// ResetDigest 在汇总发送失败时恢复上一次汇总时间，并在 retryAt 之后才允许再次领取。
+func (r *notificationRepository) ResetDigest(ctx context.Context, recipient DigestRecipient, retryAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE users SET email_digest_at = $2, email_digest_retry_at = $3 WHERE id = $1
+`, recipient.UserID, recipient.PreviousDigestAt, retryAt)
+	return err
+}
+
+// ListDigestItems 返回用户 since 之后未读且未发过邮件的通知，按时间先后排列，同时返回总数。
+func (r *notificationRepository) ListDigestItems(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]notification.Notification, int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT`+emailNotificationColumns+`
+FROM notifications n
+LEFT JOIN tasks t ON t.id = n.task_id
+LEFT JOIN users a ON a.id = n.actor_id
+WHERE n.user_id = $1 AND n.emailed_at IS NULL AND n.read_at IS NULL AND n.created_at >= $2
+ORDER BY n.created_at ASC, n.id ASC
+LIMIT $3
+`, userID, since, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]notification.Notification, 0)
+	for rows.Next() {
+		item, err := scanEmailNotification(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND emailed_at IS NULL AND read_at IS NULL AND created_at >= $2
+`, userID, since).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// MarkEmailed 将用户 since 之后、截至 until 的未发送通知标记为已通过汇总发送。
+func (r *notificationRepository) MarkEmailed(ctx context.Context, userID uuid.UUID, since, until time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE notifications SET emailed_at = $4
+WHERE user_id = $1 AND emailed_at IS NULL AND created_at >= $2 AND created_at <= $3
+`, userID, since, until, time.Now().UTC())
+	return err
+}
+
+// scanEmailNotification 扫描 emailNotificationColumns，extra 用于接收查询末尾追加的列。
+func scanEmailNotification(row rowScanner, extra ...any) (notification.Notification, error) {
+	var (
+		item    notification.Notification
+		taskID  uuid.NullUUID
+		actorID uuid.NullUUID
+	)
+	dest := []any{
+		&item.ID,
+		&item.UserID,
+		&item.Kind,
+		&taskID,
+		&item.TaskTitle,
+		&actorID,
+		&item.ActorName,
+		&item.Title,
+		&item.Body,
+		&item.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return notification.Notification{}, err
+	}
+	if taskID.Valid {
+		id := taskID.UUID
+		item.TaskID = &id
+	}
+	if actorID.Valid {
+		id := actorID.UUID
+		item.ActorID = &id
+	}
+	return item, nil
+}
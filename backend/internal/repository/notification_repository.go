@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/notification"
+)
+
+// NotificationRepository 定义站内通知相关数据库操作。
+type NotificationRepository interface {
+	Create(ctx context.Context, items []notification.Notification) (int, error)
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]notification.Notification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, id int64) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	ListAdminIDs(ctx context.Context) ([]uuid.UUID, error)
+	DispatchInstantEmails(ctx context.Context, since time.Time, limit int, lease time.Duration, send func(context.Context, EmailRecipient, notification.Notification) error) (int, error)
+	ClaimDigestRecipient(ctx context.Context, periodStart, now time.Time) (DigestRecipient, bool, error)
+	ResetDigest(ctx context.Context, recipient DigestRecipient, retryAt time.Time) error
+	ListDigestItems(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]notification.Notification, int, error)
+	MarkEmailed(ctx context.Context, userID uuid.UUID, since, until time.Time) error
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository 构造站内通知仓储。
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create 批量写入通知，DedupKey 冲突的条目会被跳过，返回实际写入数量。
+func (r *notificationRepository) Create(ctx context.Context, items []notification.Notification) (int, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	inserted := 0
+	for _, item := range items {
+		var dedupKey *string
+		if item.DedupKey != "" {
+			dedupKey = &item.DedupKey
+		}
+		res, err := tx.ExecContext(ctx, `
+INSERT INTO notifications (user_id, kind, task_id, actor_id, title, body, dedup_key, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT DO NOTHING
+`, item.UserID, item.Kind, item.TaskID, item.ActorID, item.Title, item.Body, dedupKey, now)
+		if err != nil {
+			return 0, err
+		}
+		if affected, _ := res.RowsAffected(); affected > 0 {
+			inserted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]notification.Notification, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	const query = `
+SELECT
+	n.id,
+	n.user_id,
+	n.kind,
+	n.task_id,
+	COALESCE(t.title, ''),
+	n.actor_id,
+	COALESCE(u.display_name, ''),
+	n.title,
+	n.body,
+	COALESCE(n.dedup_key, ''),
+	n.read_at,
+	n.created_at
+FROM notifications n
+LEFT JOIN tasks t ON t.id = n.task_id
+LEFT JOIN users u ON u.id = n.actor_id
+WHERE n.user_id = $1 AND ($2 = false OR n.read_at IS NULL)
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT $3 OFFSET $4
+`
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]notification.Notification, 0)
+	for rows.Next() {
+		var (
+			item    notification.Notification
+			taskID  uuid.NullUUID
+			actorID uuid.NullUUID
+			readAt  sql.NullTime
+		)
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Kind,
+			&taskID,
+			&item.TaskTitle,
+			&actorID,
+			&item.ActorName,
+			&item.Title,
+			&item.Body,
+			&item.DedupKey,
+			&readAt,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if taskID.Valid {
+			id := taskID.UUID
+			item.TaskID = &id
+		}
+		if actorID.Valid {
+			id := actorID.UUID
+			item.ActorID = &id
+		}
+		if readAt.Valid {
+			t := readAt.Time
+			item.ReadAt = &t
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+`, userID, unreadOnly).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+`, userID).Scan(&count)
+	return count, err
+}
+
+// MarkRead 将通知标记为已读，通知不属于该用户时返回 ErrNotFound，重复标记视为成功。
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, id int64) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+WITH updated AS (
+	UPDATE notifications SET read_at = COALESCE(read_at, $3)
+	WHERE id = $1 AND user_id = $2
+	RETURNING 1
+)
+SELECT EXISTS (SELECT 1 FROM updated)
+`, id, userID, time.Now().UTC()).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead 将用户全部未读通知标记为已读，返回本次标记的数量。
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL
+`, userID, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := res.RowsAffected()
+	return int(affected), nil
+}
+
+// ListAdminIDs 返回全部启用中的管理员，用于提交验收等需要管理员处理的通知。
+func (r *notificationRepository) ListAdminIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT u.id
+FROM users u
+JOIN user_roles ur ON ur.user_id = u.id AND ur.role_key = 'admin'
+WHERE u.status = 'active'
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
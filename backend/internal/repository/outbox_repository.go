@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/outbox"
+	"backend/internal/events"
+)
+
+// OutboxRepository 定义发件箱的分发操作，写入由各业务事务通过 insertOutboxTx 完成。
+type OutboxRepository interface {
+	Dispatch(ctx context.Context, limit int, lease time.Duration, handle func(context.Context, outbox.Message) ([]string, error), retryDelay func(attempts int) time.Duration) (int, error)
+}
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository 构造发件箱仓储。
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Dispatch 领取最多 limit 条到期事件并逐条交给 handle：全部目标成功的删除，否则记录已送达的目标并按 retryDelay 推迟。
+// 领取时将 available_at 推迟 lease 作为租约并立即提交，handle 在事务外执行，不会长时间持有行锁；
+// 多副本并发分发时借助 SKIP LOCKED 各自领取不同的事件，进程在记录结果前退出时租约到期后重新分发。
+func (r *outboxRepository) Dispatch(ctx context.Context, limit int, lease time.Duration, handle func(context.Context, outbox.Message) ([]string, error), retryDelay func(attempts int) time.Duration) (int, error) {
+	now := time.Now().UTC()
+	rows, err := r.db.QueryContext(ctx, `
+WITH due AS (
+	SELECT id
+	FROM outbox
+	WHERE available_at <= $1
+	ORDER BY id ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE outbox o
+SET available_at = $3
+FROM due
+WHERE o.id = due.id
+RETURNING o.id, o.topic, o.aggregate_id, o.payload, o.attempts, o.delivered, o.created_at
+`, now, limit, now.Add(lease))
+	if err != nil {
+		return 0, err
+	}
+	messages := make([]outbox.Message, 0)
+	for rows.Next() {
+		var (
+			msg          outbox.Message
+			payload      []byte
+			deliveredRaw []byte
+		)
+		if err := rows.Scan(&msg.ID, &msg.Topic, &msg.AggregateID, &payload, &msg.Attempts, &deliveredRaw, &msg.CreatedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		msg.Payload = payload
+		if err := json.Unmarshal(deliveredRaw, &msg.Delivered); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	// UPDATE ... RETURNING 不保证顺序，按写入顺序分发。
+	slices.SortFunc(messages, func(a, b outbox.Message) int { return cmp.Compare(a.ID, b.ID) })
+
+	for _, msg := range messages {
+		delivered, handleErr := handle(ctx, msg)
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if handleErr == nil {
+			if _, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = $1`, msg.ID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		deliveredRaw, err := marshalTags(delivered)
+		if err != nil {
+			return 0, err
+		}
+		retryAt := time.Now().UTC().Add(retryDelay(msg.Attempts + 1))
+		if _, err := r.db.ExecContext(ctx, `
+UPDATE outbox SET attempts = attempts + 1, last_error = $2, available_at = $3, delivered = $4 WHERE id = $1
+`, msg.ID, handleErr.Error(), retryAt, deliveredRaw); err != nil {
+			return 0, err
+		}
+	}
+	return len(messages), nil
+}
+
+// insertOutboxTx 在调用方事务内写入一条待分发事件，随事务一起提交或回滚。
+func insertOutboxTx(ctx context.Context, tx *sql.Tx, topic, aggregateID string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO outbox (topic, aggregate_id, payload, available_at, created_at)
+VALUES ($1, $2, $3, $4, $4)
+`, topic, aggregateID, string(raw), time.Now().UTC())
+	return err
+}
+
+// taskEventPayload 是推送到看板、webhook 与群机器人的任务变化摘要，客户端据此局部刷新或重新拉取详情。
+// 内容为事件发生时的任务快照，分发目标无需再回查任务。
+type taskEventPayload struct {
+	TaskID     string   `json:"taskId"`
+	Title      string   `json:"title,omitempty"`
+	Status     string   `json:"status"`
+	Priority   string   `json:"priority"`
+	Bounty     int64    `json:"bounty"`
+	Deadline   *string  `json:"deadline,omitempty"`
+	Tags       []string `json:"tags"`
+	Assignee   *string  `json:"assignee,omitempty"`
+	AssigneeID *string  `json:"assigneeId,omitempty"`
+	CreatedBy  string   `json:"createdBy"`
+	ParentID   *string  `json:"parentId,omitempty"`
+	ActorID    *string  `json:"actorId,omitempty"`
+	Action     string   `json:"action"`
+	Comment    *string  `json:"comment,omitempty"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+// insertTaskEventTx 读取任务在本事务内的最新状态并写入发件箱，由 insertTaskAuditTx 在每次任务变更时调用。
+// Bounty 为含子任务的总赏金，Assignee 为当前领取人的用户名；验收后领取已结束，
+// AssigneeID 与验收意见取自审计附加信息中的 assigneeId 与 comment。
+func insertTaskEventTx(ctx context.Context, tx *sql.Tx, actor uuid.UUID, action audit.Action, taskID uuid.UUID, extra map[string]any) error {
+	var (
+		payload    = taskEventPayload{TaskID: taskID.String(), Action: string(action)}
+		parentID   uuid.NullUUID
+		deadline   sql.NullTime
+		tagsRaw    []byte
+		assignee   sql.NullString
+		assigneeID uuid.NullUUID
+		updatedAt  time.Time
+	)
+	if err := tx.QueryRowContext(ctx, `
+SELECT
+	t.title,
+	t.status,
+	t.priority,
+	t.bounty + COALESCE(ch.child_bounty, 0),
+	t.deadline,
+	COALESCE((
+		SELECT jsonb_agg(tg.name ORDER BY tg.name)
+		FROM task_tag_map m
+		JOIN task_tags tg ON tg.id = m.tag_id
+		WHERE m.task_id = t.id
+	), '[]'::jsonb),
+	a.username,
+	a.user_id,
+	t.created_by,
+	t.parent_id,
+	t.updated_at
+FROM tasks t
+LEFT JOIN LATERAL (
+`+childSummaryQuery+`
+) ch ON true
+LEFT JOIN LATERAL (
+	SELECT ta.user_id, u.username
+	FROM task_assignments ta
+	JOIN users u ON u.id = ta.user_id
+	WHERE ta.task_id = t.id AND ta.status IN ('claimed', 'submitted')
+	ORDER BY ta.created_at DESC
+	LIMIT 1
+) a ON true
+WHERE t.id = $1
+`, taskID).Scan(
+		&payload.Title,
+		&payload.Status,
+		&payload.Priority,
+		&payload.Bounty,
+		&deadline,
+		&tagsRaw,
+		&assignee,
+		&assigneeID,
+		&payload.CreatedBy,
+		&parentID,
+		&updatedAt,
+	); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(tagsRaw, &payload.Tags); err != nil {
+		return err
+	}
+	payload.UpdatedAt = updatedAt.UTC().Format(time.RFC3339)
+	if deadline.Valid {
+		val := deadline.Time.UTC().Format(time.RFC3339)
+		payload.Deadline = &val
+	}
+	if assignee.Valid {
+		val := assignee.String
+		payload.Assignee = &val
+	}
+	if val, ok := extra["assigneeId"].(string); ok {
+		payload.AssigneeID = &val
+	} else if assigneeID.Valid {
+		val := assigneeID.UUID.String()
+		payload.AssigneeID = &val
+	}
+	if val, ok := extra["comment"].(string); ok {
+		payload.Comment = &val
+	}
+	if parentID.Valid {
+		val := parentID.UUID.String()
+		payload.ParentID = &val
+	}
+	if actor != uuid.Nil {
+		val := actor.String()
+		payload.ActorID = &val
+	}
+	return insertOutboxTx(ctx, tx, taskEventTopic(action), payload.TaskID, payload)
+}
+
+// taskEventTopic 将审计动作映射为对外的事件类型，未单独列出的变更统一为 task.updated。
+func taskEventTopic(action audit.Action) string {
+	switch action {
+	case audit.ActionTaskCreate:
+		return events.TypeTaskCreated
+	case audit.ActionTaskPublish:
+		return events.TypeTaskPublished
+	case audit.ActionTaskClaim:
+		return events.TypeTaskClaimed
+	case audit.ActionTaskSubmit:
+		return events.TypeTaskSubmitted
+	case audit.ActionTaskReject:
+		return events.TypeTaskRejected
+	case audit.ActionTaskComplete:
+		return events.TypeTaskCompleted
+	default:
+		return events.TypeTaskUpdated
+	}
+}
@@ -6,14 +6,36 @@ import (
 
 // Registry 聚合仓储接口实例。
 type Registry struct {
-	User UserRepository
-	Task TaskRepository
+	User         UserRepository
+	Task         TaskRepository
+	Ledger       LedgerRepository
+	Leaderboard  LeaderboardRepository
+	Audit        AuditRepository
+	Template     TemplateRepository
+	Schedule     ScheduleRepository
+	Comment      CommentRepository
+	Attachment   AttachmentRepository
+	Notification NotificationRepository
+	Webhook      WebhookRepository
+	Outbox       OutboxRepository
+	ChatChannel  ChatChannelRepository
 }
 
 // NewRegistry 根据数据库连接创建仓储实例。
 func NewRegistry(db *sql.DB) Registry {
 	return Registry{
-		User: NewUserRepository(db),
-		Task: NewTaskRepository(db),
+		User:         NewUserRepository(db),
+		Task:         NewTaskRepository(db),
+		Ledger:       NewLedgerRepository(db),
+		Leaderboard:  NewLeaderboardRepository(db),
+		Audit:        NewAuditRepository(db),
+		Template:     NewTemplateRepository(db),
+		Schedule:     NewScheduleRepository(db),
+		Comment:      NewCommentRepository(db),
+		Attachment:   NewAttachmentRepository(db),
+		Notification: NewNotificationRepository(db),
+		Webhook:      NewWebhookRepository(db),
+		Outbox:       NewOutboxRepository(db),
+		ChatChannel:  NewChatChannelRepository(db),
 	}
 }
@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// ScheduleRepository 定义周期任务计划相关数据库操作。
+type ScheduleRepository interface {
+	List(ctx context.Context) ([]task.Schedule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (task.Schedule, error)
+	Create(ctx context.Context, sch task.Schedule) (task.Schedule, error)
+	Update(ctx context.Context, sch task.Schedule, actor uuid.UUID) (task.Schedule, error)
+	SetPaused(ctx context.Context, id uuid.UUID, paused bool, nextRunAt *time.Time, actor uuid.UUID) (task.Schedule, error)
+	Delete(ctx context.Context, id, actor uuid.UUID) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]task.Schedule, error)
+	ClaimOccurrence(ctx context.Context, id uuid.UUID, occurrence, next time.Time) (bool, error)
+	ReclaimStaleRuns(ctx context.Context, staleBefore, now time.Time, limit int) ([]ScheduleRun, error)
+	RecordRun(ctx context.Context, id uuid.UUID, occurrence time.Time, taskID *uuid.UUID, runErr string) error
+}
+
+// ErrScheduleRunDone 表示该次触发已记录结果（例如已由其他副本发布），不应再次发布。
+var ErrScheduleRunDone = errors.New("repository: schedule run already recorded")
+
+// ScheduleRun 标识一次已认领的触发。
+type ScheduleRun struct {
+	ScheduleID uuid.UUID
+	Occurrence time.Time
+}
+
+type scheduleRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository 构造周期任务计划仓储。
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+const scheduleColumns = `id, name, cron_expr, timezone, title, description_html, bounty, priority, tags, deadline_offset_minutes, paused, next_run_at, last_run_at, created_by, created_at, updated_at`
+
+func (r *scheduleRepository) List(ctx context.Context) ([]task.Schedule, error) {
+	return r.query(ctx, `SELECT `+scheduleColumns+` FROM task_schedules ORDER BY name ASC, created_at ASC`)
+}
+
+func (r *scheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (task.Schedule, error) {
+	sch, err := scanSchedule(r.db.QueryRowContext(ctx, `SELECT `+scheduleColumns+` FROM task_schedules WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Schedule{}, ErrNotFound
+	}
+	return sch, err
+}
+
+func (r *scheduleRepository) Create(ctx context.Context, sch task.Schedule) (task.Schedule, error) {
+	tagsRaw, err := marshalTags(sch.Tags)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	created, err := scanSchedule(tx.QueryRowContext(ctx, `
+INSERT INTO task_schedules (id, name, cron_expr, timezone, title, description_html, bounty, priority, tags, deadline_offset_minutes, paused, next_run_at, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+RETURNING `+scheduleColumns,
+		uuid.New(),
+		sch.Name,
+		sch.CronExpr,
+		sch.Timezone,
+		sch.Title,
+		sch.DescriptionHTML,
+		sch.Bounty,
+		string(sch.Priority),
+		tagsRaw,
+		int(sch.DeadlineOffset/time.Minute),
+		sch.Paused,
+		sch.NextRunAt,
+		sch.CreatedBy,
+		now,
+	))
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	if err := insertScheduleAuditTx(ctx, tx, sch.CreatedBy, audit.ActionScheduleCreate, created); err != nil {
+		return task.Schedule{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task.Schedule{}, err
+	}
+	return created, nil
+}
+
+func (r *scheduleRepository) Update(ctx context.Context, sch task.Schedule, actor uuid.UUID) (task.Schedule, error) {
+	tagsRaw, err := marshalTags(sch.Tags)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+	defer tx.Rollback()
+
+	updated, err := scanSchedule(tx.QueryRowContext(ctx, `
+UPDATE task_schedules
+SET name = $2,
+	cron_expr = $3,
+	timezone = $4,
+	title = $5,
+	description_html = $6,
+	bounty = $7,
+	priority = $8,
+	tags = $9,
+	deadline_offset_minutes = $10,
+	next_run_at = $11,
+	updated_at = $12
+WHERE id = $1
+RETURNING `+scheduleColumns,
+		sch.ID,
+		sch.Name,
+		sch.CronExpr,
+		sch.Timezone,
+		sch.Title,
+		sch.DescriptionHTML,
+		sch.Bounty,
+		string(sch.Priority),
+		tagsRaw,
+		int(sch.DeadlineOffset/time.Minute),
+		sch.NextRunAt,
+		time.Now().UTC(),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Schedule{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	if err := insertScheduleAuditTx(ctx, tx, actor, audit.ActionScheduleUpdate, updated); err != nil {
+		return task.Schedule{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task.Schedule{}, err
+	}
+	return updated, nil
+}
+
+func (r *scheduleRepository) SetPaused(ctx context.Context, id uuid.UUID, paused bool, nextRunAt *time.Time, actor uuid.UUID) (task.Schedule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+	defer tx.Rollback()
+
+	updated, err := scanSchedule(tx.QueryRowContext(ctx, `
+UPDATE task_schedules
+SET paused = $2,
+	next_run_at = $3,
+	updated_at = $4
+WHERE id = $1
+RETURNING `+scheduleColumns, id, paused, nextRunAt, time.Now().UTC()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Schedule{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	action := audit.ActionScheduleResume
+	if paused {
+		action = audit.ActionSchedulePause
+	}
+	if err := insertScheduleAuditTx(ctx, tx, actor, action, updated); err != nil {
+		return task.Schedule{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task.Schedule{}, err
+	}
+	return updated, nil
+}
+
+func (r *scheduleRepository) Delete(ctx context.Context, id, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deleted, err := scanSchedule(tx.QueryRowContext(ctx, `DELETE FROM task_schedules WHERE id = $1 RETURNING `+scheduleColumns, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertScheduleAuditTx(ctx, tx, actor, audit.ActionScheduleDelete, deleted); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *scheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]task.Schedule, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return r.query(ctx, `
+SELECT `+scheduleColumns+`
+FROM task_schedules
+WHERE paused = FALSE
+	AND next_run_at IS NOT NULL
+	AND next_run_at <= $1
+ORDER BY next_run_at ASC
+LIMIT $2
+`, now, limit)
+}
+
+// ClaimOccurrence 认领一次触发：仅当计划仍指向该触发时间时推进 next_run_at 并写入触发记录。
+// 多个副本同时认领时只有一个会成功，其余返回 false。
+func (r *scheduleRepository) ClaimOccurrence(ctx context.Context, id uuid.UUID, occurrence, next time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+UPDATE task_schedules
+SET next_run_at = $3,
+	last_run_at = $2
+WHERE id = $1
+	AND paused = FALSE
+	AND next_run_at = $2
+`, id, occurrence, next)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	result, err = tx.ExecContext(ctx, `
+INSERT INTO task_schedule_runs (schedule_id, occurrence, pending, claimed_at)
+VALUES ($1, $2, TRUE, NOW())
+ON CONFLICT (schedule_id, occurrence) DO NOTHING
+`, id, occurrence)
+	if err != nil {
+		return false, err
+	}
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReclaimStaleRuns 重新认领 staleBefore 之前认领但仍未记录结果的触发（例如发布前进程退出），
+// 并将认领时间刷新为 now，避免多个副本同时接手。
+func (r *scheduleRepository) ReclaimStaleRuns(ctx context.Context, staleBefore, now time.Time, limit int) ([]ScheduleRun, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+WITH stale AS (
+	SELECT schedule_id, occurrence
+	FROM task_schedule_runs
+	WHERE pending AND claimed_at < $1
+	ORDER BY claimed_at ASC
+	LIMIT $3
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE task_schedule_runs r
+SET claimed_at = $2
+FROM stale
+WHERE r.schedule_id = stale.schedule_id AND r.occurrence = stale.occurrence
+RETURNING r.schedule_id, r.occurrence
+`, staleBefore, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make([]ScheduleRun, 0)
+	for rows.Next() {
+		var run ScheduleRun
+		if err := rows.Scan(&run.ScheduleID, &run.Occurrence); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// RecordRun 记录仍待发布的触发的结果；已由其他副本记录结果的触发保持不变。
+func (r *scheduleRepository) RecordRun(ctx context.Context, id uuid.UUID, occurrence time.Time, taskID *uuid.UUID, runErr string) error {
+	_, err := r.db.ExecContext(ctx, `
+UPDATE task_schedule_runs
+SET task_id = $3,
+	error = NULLIF($4, ''),
+	pending = FALSE
+WHERE schedule_id = $1 AND occurrence = $2 AND pending
+`, id, occurrence, taskID, runErr)
+	return err
+}
+
+// completeScheduleRunTx 在创建任务的事务内记录触发结果；触发已不再待发布时返回 ErrScheduleRunDone，
+// 调用方回滚事务，保证同一触发只会发布一个任务。
+func completeScheduleRunTx(ctx context.Context, tx *sql.Tx, run ScheduleRun, taskID uuid.UUID) error {
+	result, err := tx.ExecContext(ctx, `
+UPDATE task_schedule_runs
+SET task_id = $3,
+	error = NULL,
+	pending = FALSE
+WHERE schedule_id = $1 AND occurrence = $2 AND pending
+`, run.ScheduleID, run.Occurrence, taskID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrScheduleRunDone
+	}
+	return nil
+}
+
+func (r *scheduleRepository) query(ctx context.Context, query string, args ...any) ([]task.Schedule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make([]task.Schedule, 0)
+	for rows.Next() {
+		sch, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, sch)
+	}
+	return schedules, rows.Err()
+}
+
+func insertScheduleAuditTx(ctx context.Context, tx *sql.Tx, actor uuid.UUID, action audit.Action, sch task.Schedule) error {
+	return insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     action,
+		Resource:   "task_schedule",
+		ResourceID: sch.ID.String(),
+		Metadata: map[string]any{
+			"name":     sch.Name,
+			"cronExpr": sch.CronExpr,
+			"timezone": sch.Timezone,
+			"paused":   sch.Paused,
+		},
+	})
+}
+
+func scanSchedule(row rowScanner) (task.Schedule, error) {
+	var (
+		sch           task.Schedule
+		tagsRaw       []byte
+		offsetMinutes int
+		nextRunAt     sql.NullTime
+		lastRunAt     sql.NullTime
+	)
+	if err := row.Scan(
+		&sch.ID,
+		&sch.Name,
+		&sch.CronExpr,
+		&sch.Timezone,
+		&sch.Title,
+		&sch.DescriptionHTML,
+		&sch.Bounty,
+		&sch.Priority,
+		&tagsRaw,
+		&offsetMinutes,
+		&sch.Paused,
+		&nextRunAt,
+		&lastRunAt,
+		&sch.CreatedBy,
+		&sch.CreatedAt,
+		&sch.UpdatedAt,
+	); err != nil {
+		return task.Schedule{}, err
+	}
+	sch.Tags = make([]string, 0)
+	if len(tagsRaw) > 0 {
+		if err := json.Unmarshal(tagsRaw, &sch.Tags); err != nil {
+			return task.Schedule{}, err
+		}
+	}
+	sch.DeadlineOffset = time.Duration(offsetMinutes) * time.Minute
+	if nextRunAt.Valid {
+		t := nextRunAt.Time
+		sch.NextRunAt = &t
+	}
+	if lastRunAt.Valid {
+		t := lastRunAt.Time
+		sch.LastRunAt = &t
+	}
+	return sch, nil
+}
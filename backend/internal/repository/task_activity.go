@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+)
+
+// ListActivity 汇总任务的审计日志与领取记录，按时间正序返回。
+// 早于审计日志上线的领取、释放、完成记录由 task_assignments 补齐，已有审计的记录不会重复出现。
+func (r *taskRepository) ListActivity(ctx context.Context, taskID uuid.UUID) ([]task.Activity, error) {
+	const query = `
+SELECT kind, actor_id, actor_name, assignee_name, metadata, occurred_at
+FROM (
+	SELECT
+		al.action AS kind,
+		al.user_id AS actor_id,
+		COALESCE(u.display_name, '') AS actor_name,
+		COALESCE(au.display_name, '') AS assignee_name,
+		al.metadata,
+		al.created_at AS occurred_at,
+		al.id AS seq
+	FROM audit_logs al
+	LEFT JOIN users u ON u.id = al.user_id
+	LEFT JOIN users au ON au.id::text = al.metadata->>'assigneeId'
+	WHERE al.resource = 'task' AND al.resource_id = $1
+
+	UNION ALL
+
+	SELECT
+		'task_create',
+		t.created_by,
+		u.display_name,
+		'',
+		jsonb_build_object('toStatus', 'draft'),
+		t.created_at,
+		0
+	FROM tasks t
+	JOIN users u ON u.id = t.created_by
+	WHERE t.id = $2
+		AND NOT EXISTS (
+			SELECT 1 FROM audit_logs al
+			WHERE al.resource = 'task' AND al.resource_id = $1 AND al.action = 'task_create'
+		)
+
+	UNION ALL
+
+	SELECT
+		ev.kind,
+		ev.actor_id,
+		COALESCE(ev.actor_name, ''),
+		u.display_name,
+		jsonb_build_object('assignmentId', ta.id, 'assigneeId', ta.user_id),
+		ev.occurred_at,
+		0
+	FROM task_assignments ta
+	JOIN users u ON u.id = ta.user_id
+	CROSS JOIN LATERAL (
+		VALUES
+			('task_claim', ta.user_id, u.display_name, ta.created_at),
+			('task_release', ta.user_id, u.display_name, ta.released_at),
+			('task_complete', NULL::uuid, NULL::text, ta.completed_at)
+	) AS ev(kind, actor_id, actor_name, occurred_at)
+	WHERE ta.task_id = $2
+		AND ev.occurred_at IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM audit_logs al
+			WHERE al.resource = 'task'
+				AND al.resource_id = $1
+				AND al.action = ev.kind
+				AND al.metadata->>'assignmentId' = ta.id::text
+		)
+) activity
+ORDER BY occurred_at ASC, seq ASC
+`
+	rows, err := r.db.QueryContext(ctx, query, taskID.String(), taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]task.Activity, 0)
+	for rows.Next() {
+		var (
+			item     task.Activity
+			actorID  sql.NullString
+			metaRaw  []byte
+			metadata map[string]any
+		)
+		if err := rows.Scan(&item.Kind, &actorID, &item.ActorName, &item.AssigneeName, &metaRaw, &item.OccurredAt); err != nil {
+			return nil, err
+		}
+		if actorID.Valid {
+			if id, err := uuid.Parse(actorID.String); err == nil {
+				item.ActorID = &id
+			}
+		}
+		if len(metaRaw) > 0 {
+			if err := json.Unmarshal(metaRaw, &metadata); err != nil {
+				return nil, err
+			}
+		}
+		if from, ok := metadata["fromStatus"].(string); ok {
+			item.FromStatus = task.Status(from)
+			delete(metadata, "fromStatus")
+		}
+		if to, ok := metadata["toStatus"].(string); ok {
+			item.ToStatus = task.Status(to)
+			delete(metadata, "toStatus")
+		}
+		item.Details = metadata
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// checklistSummaryQuery 统计外层任务 t 的检查清单条目总数与已勾选数。
+const checklistSummaryQuery = `
+	SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE ci.done) AS done
+	FROM task_checklist_items ci
+	WHERE ci.task_id = t.id`
+
+// ChecklistItemUpdateInput 描述检查清单条目的可选修改字段。
+type ChecklistItemUpdateInput struct {
+	TaskID   uuid.UUID
+	ItemID   int64
+	Text     *string
+	Position *int
+	ActorID  uuid.UUID
+}
+
+// AddChecklistItem 在检查清单末尾追加一条。
+func (r *taskRepository) AddChecklistItem(ctx context.Context, taskID uuid.UUID, text string, actor uuid.UUID) (task.Task, error) {
+	tx, status, err := r.beginChecklistTx(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.QueryRowContext(ctx, `
+INSERT INTO task_checklist_items (task_id, position, text, created_at, updated_at)
+SELECT $1, COALESCE(MAX(position), 0) + 1, $2, $3, $3
+FROM task_checklist_items WHERE task_id = $1
+RETURNING id
+`, taskID, text, time.Now().UTC()).Scan(&id); err != nil {
+		return task.Task{}, err
+	}
+
+	if err := insertTaskAuditTx(ctx, tx, actor, audit.ActionChecklistAdd, taskID, status, status, nil, map[string]any{
+		"itemId": id,
+		"text":   text,
+	}); err != nil {
+		return task.Task{}, err
+	}
+	return r.commitChecklistTx(ctx, tx, taskID)
+}
+
+// UpdateChecklistItem 修改条目文本或位置，位置变化时其余条目顺延。
+func (r *taskRepository) UpdateChecklistItem(ctx context.Context, input ChecklistItemUpdateInput) (task.Task, error) {
+	tx, status, err := r.beginChecklistTx(ctx, input.TaskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	item, err := getChecklistItemTx(ctx, tx, input.TaskID, input.ItemID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	changes := map[string]any{}
+	now := time.Now().UTC()
+	if input.Text != nil && *input.Text != item.Text {
+		if _, err := tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET text = $2, updated_at = $3 WHERE id = $1
+`, item.ID, *input.Text, now); err != nil {
+			return task.Task{}, err
+		}
+		changes["text"] = fieldChange(item.Text, *input.Text)
+	}
+
+	if input.Position != nil && *input.Position != item.Position {
+		var count int
+		if err := tx.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM task_checklist_items WHERE task_id = $1
+`, input.TaskID).Scan(&count); err != nil {
+			return task.Task{}, err
+		}
+		target := min(max(*input.Position, 1), count)
+
+		// 先把其余条目按新顺序挪位，再放置当前条目，保持 position 连续。
+		if target < item.Position {
+			_, err = tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET position = position + 1
+WHERE task_id = $1 AND position >= $2 AND position < $3
+`, input.TaskID, target, item.Position)
+		} else {
+			_, err = tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET position = position - 1
+WHERE task_id = $1 AND position > $2 AND position <= $3
+`, input.TaskID, item.Position, target)
+		}
+		if err != nil {
+			return task.Task{}, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET position = $2, updated_at = $3 WHERE id = $1
+`, item.ID, target, now); err != nil {
+			return task.Task{}, err
+		}
+		if target != item.Position {
+			changes["position"] = fieldChange(item.Position, target)
+		}
+	}
+
+	if len(changes) > 0 {
+		if err := insertTaskAuditTx(ctx, tx, input.ActorID, audit.ActionChecklistEdit, input.TaskID, status, status, changes, map[string]any{
+			"itemId": item.ID,
+		}); err != nil {
+			return task.Task{}, err
+		}
+	}
+	return r.commitChecklistTx(ctx, tx, input.TaskID)
+}
+
+// DeleteChecklistItem 删除条目并收拢后续条目的位置。
+func (r *taskRepository) DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID int64, actor uuid.UUID) (task.Task, error) {
+	tx, status, err := r.beginChecklistTx(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	item, err := getChecklistItemTx(ctx, tx, taskID, itemID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_checklist_items WHERE id = $1`, item.ID); err != nil {
+		return task.Task{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET position = position - 1
+WHERE task_id = $1 AND position > $2
+`, taskID, item.Position); err != nil {
+		return task.Task{}, err
+	}
+
+	if err := insertTaskAuditTx(ctx, tx, actor, audit.ActionChecklistRemove, taskID, status, status, nil, map[string]any{
+		"itemId": item.ID,
+		"text":   item.Text,
+	}); err != nil {
+		return task.Task{}, err
+	}
+	return r.commitChecklistTx(ctx, tx, taskID)
+}
+
+// SetChecklistItemDone 勾选或取消勾选条目，状态未变化时不写审计。
+func (r *taskRepository) SetChecklistItemDone(ctx context.Context, taskID uuid.UUID, itemID int64, done bool, actor uuid.UUID) (task.Task, error) {
+	tx, status, err := r.beginChecklistTx(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	item, err := getChecklistItemTx(ctx, tx, taskID, itemID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	if item.Done != done {
+		var (
+			doneBy *uuid.UUID
+			doneAt *time.Time
+			action = audit.ActionChecklistUncheck
+		)
+		now := time.Now().UTC()
+		if done {
+			doneBy, doneAt, action = &actor, &now, audit.ActionChecklistCheck
+		}
+		if _, err := tx.ExecContext(ctx, `
+UPDATE task_checklist_items SET done = $2, done_by = $3, done_at = $4, updated_at = $5 WHERE id = $1
+`, item.ID, done, doneBy, doneAt, now); err != nil {
+			return task.Task{}, err
+		}
+		if err := insertTaskAuditTx(ctx, tx, actor, action, taskID, status, status, nil, map[string]any{
+			"itemId": item.ID,
+			"text":   item.Text,
+		}); err != nil {
+			return task.Task{}, err
+		}
+	}
+	return r.commitChecklistTx(ctx, tx, taskID)
+}
+
+// beginChecklistTx 开启事务并锁定任务，已完成或归档的任务不允许修改清单。
+func (r *taskRepository) beginChecklistTx(ctx context.Context, taskID uuid.UUID) (*sql.Tx, task.Status, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	status, _, err := r.lockTaskTx(ctx, tx, taskID)
+	if err != nil {
+		tx.Rollback()
+		return nil, "", err
+	}
+	if status == task.StatusCompleted || status == task.StatusArchived {
+		tx.Rollback()
+		return nil, "", fmt.Errorf("%w: task is %s", ErrConflict, status)
+	}
+	return tx, status, nil
+}
+
+func (r *taskRepository) commitChecklistTx(ctx context.Context, tx *sql.Tx, taskID uuid.UUID) (task.Task, error) {
+	tk, err := r.fetchTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Task{}, err
+	}
+	return tk, nil
+}
+
+func getChecklistItemTx(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, itemID int64) (task.ChecklistItem, error) {
+	var item task.ChecklistItem
+	err := tx.QueryRowContext(ctx, `
+SELECT id, task_id, position, text, done
+FROM task_checklist_items
+WHERE id = $1 AND task_id = $2
+FOR UPDATE
+`, itemID, taskID).Scan(&item.ID, &item.TaskID, &item.Position, &item.Text, &item.Done)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.ChecklistItem{}, ErrNotFound
+	}
+	return item, err
+}
+
+// attachChecklistTx 按位置顺序加载任务的检查清单条目。
+func attachChecklistTx(ctx context.Context, q queryer, tk *task.Task) error {
+	rows, err := q.QueryContext(ctx, `
+SELECT ci.id, ci.task_id, ci.position, ci.text, ci.done, ci.done_by, COALESCE(u.display_name, ''), ci.done_at, ci.created_at, ci.updated_at
+FROM task_checklist_items ci
+LEFT JOIN users u ON u.id = ci.done_by
+WHERE ci.task_id = $1
+ORDER BY ci.position ASC, ci.id ASC
+`, tk.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	items := make([]task.ChecklistItem, 0)
+	for rows.Next() {
+		var (
+			item   task.ChecklistItem
+			doneBy uuid.NullUUID
+			doneAt sql.NullTime
+		)
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Position, &item.Text, &item.Done, &doneBy, &item.DoneByName, &doneAt, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return err
+		}
+		if doneBy.Valid {
+			id := doneBy.UUID
+			item.DoneBy = &id
+		}
+		if doneAt.Valid {
+			t := doneAt.Time
+			item.DoneAt = &t
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	tk.Checklist = items
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// MarkOverdue 将截止时间早于 now 且仍处于领取状态的任务标记为逾期，每个任务只标记一次。
+func (r *taskRepository) MarkOverdue(ctx context.Context, now time.Time) ([]OverdueClaim, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const query = `
+UPDATE tasks t
+SET overdue_at = $1
+FROM task_assignments ta
+WHERE ta.task_id = t.id
+	AND ta.status = 'claimed'
+	AND t.status = 'claimed'
+	AND t.deleted_at IS NULL
+	AND t.overdue_at IS NULL
+	AND t.deadline IS NOT NULL
+	AND t.deadline < $1
+RETURNING t.id, ta.user_id, t.deadline
+`
+	rows, err := tx.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]OverdueClaim, 0)
+	for rows.Next() {
+		var claim OverdueClaim
+		if err := rows.Scan(&claim.TaskID, &claim.UserID, &claim.Deadline); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	for _, claim := range claims {
+		extra := map[string]any{
+			"assigneeId": claim.UserID.String(),
+			"deadline":   claim.Deadline.UTC().Format(time.RFC3339),
+		}
+		if err := insertTaskAuditTx(ctx, tx, uuid.Nil, audit.ActionTaskOverdue, claim.TaskID, task.StatusClaimed, task.StatusClaimed, nil, extra); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ListOverdueClaims 返回截止时间早于 cutoff 且仍处于领取状态的任务，按截止时间先后排列。
+func (r *taskRepository) ListOverdueClaims(ctx context.Context, cutoff time.Time, limit int) ([]OverdueClaim, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	const query = `
+SELECT t.id, ta.user_id, t.deadline
+FROM tasks t
+JOIN task_assignments ta ON ta.task_id = t.id AND ta.status = 'claimed'
+WHERE t.status = 'claimed'
+	AND t.deleted_at IS NULL
+	AND t.deadline IS NOT NULL
+	AND t.deadline < $1
+ORDER BY t.deadline ASC
+LIMIT $2
+`
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claims := make([]OverdueClaim, 0)
+	for rows.Next() {
+		var claim OverdueClaim
+		if err := rows.Scan(&claim.TaskID, &claim.UserID, &claim.Deadline); err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
+
+// ListDueSoonClaims 返回截止时间落在 (from, until] 内且仍处于领取状态、尚未提醒过的任务，用于截止提醒。
+// 已提醒的判断与 NotificationService.NotifyDeadlines 生成的去重键保持一致，limit 只作用于待提醒的领取。
+func (r *taskRepository) ListDueSoonClaims(ctx context.Context, from, until time.Time, limit int) ([]OverdueClaim, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	const query = `
+SELECT t.id, ta.user_id, t.deadline
+FROM tasks t
+JOIN task_assignments ta ON ta.task_id = t.id AND ta.status = 'claimed'
+WHERE t.status = 'claimed'
+	AND t.deleted_at IS NULL
+	AND t.deadline > $1
+	AND t.deadline <= $2
+	AND NOT EXISTS (
+		SELECT 1 FROM notifications n
+		WHERE n.user_id = ta.user_id
+			AND n.dedup_key = 'deadline:' || t.id::text || ':' || ta.user_id::text || ':' || FLOOR(EXTRACT(EPOCH FROM t.deadline))::bigint::text
+	)
+ORDER BY t.deadline ASC
+LIMIT $3
+`
+	rows, err := r.db.QueryContext(ctx, query, from, until, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claims := make([]OverdueClaim, 0)
+	for rows.Next() {
+		var claim OverdueClaim
+		if err := rows.Scan(&claim.TaskID, &claim.UserID, &claim.Deadline); err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
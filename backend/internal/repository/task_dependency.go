@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// ErrDependencyCycle 表示新增的依赖会形成环。
+var ErrDependencyCycle = errors.New("repository: dependency cycle")
+
+// openBlockersQuery 选出外层任务 t 尚未完成的前置任务，供列表过滤与领取守卫使用。
+const openBlockersQuery = `
+	SELECT 1 FROM task_dependencies d
+	JOIN tasks b ON b.id = d.blocked_by
+	WHERE d.task_id = t.id
+		AND b.deleted_at IS NULL
+		AND b.status <> 'completed'`
+
+// queryer 同时适配 *sql.DB 与 *sql.Tx。
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// AddDependency 声明 taskID 被 blockerID 阻塞，重复声明视为成功，形成环时返回 ErrDependencyCycle。
+func (r *taskRepository) AddDependency(ctx context.Context, taskID, blockerID, actor uuid.UUID) (task.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	// 串行化依赖写入，避免两个并发插入各自通过环检测后共同成环。
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('task_dependencies'))`); err != nil {
+		return task.Task{}, err
+	}
+
+	status, _, err := r.lockTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if status == task.StatusCompleted || status == task.StatusArchived {
+		return task.Task{}, fmt.Errorf("%w: task is %s", ErrConflict, status)
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `
+SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)
+`, blockerID).Scan(&exists); err != nil {
+		return task.Task{}, err
+	}
+	if !exists {
+		return task.Task{}, ErrNotFound
+	}
+
+	var cycle bool
+	if err := tx.QueryRowContext(ctx, `
+WITH RECURSIVE chain (id) AS (
+	SELECT blocked_by FROM task_dependencies WHERE task_id = $1
+	UNION
+	SELECT d.blocked_by FROM task_dependencies d JOIN chain c ON d.task_id = c.id
+)
+SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)
+`, blockerID, taskID).Scan(&cycle); err != nil {
+		return task.Task{}, err
+	}
+	if cycle {
+		return task.Task{}, ErrDependencyCycle
+	}
+
+	res, err := tx.ExecContext(ctx, `
+INSERT INTO task_dependencies (task_id, blocked_by, created_by)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`, taskID, blockerID, actor)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		if err := insertTaskAuditTx(ctx, tx, actor, audit.ActionTaskDependencyAdd, taskID, status, status, nil, map[string]any{
+			"blockedBy": blockerID.String(),
+		}); err != nil {
+			return task.Task{}, err
+		}
+	}
+
+	tk, err := r.fetchTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Task{}, err
+	}
+	return tk, nil
+}
+
+// RemoveDependency 解除 taskID 对 blockerID 的依赖。
+func (r *taskRepository) RemoveDependency(ctx context.Context, taskID, blockerID, actor uuid.UUID) (task.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	status, _, err := r.lockTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by = $2
+`, taskID, blockerID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return task.Task{}, ErrNotFound
+	}
+
+	if err := insertTaskAuditTx(ctx, tx, actor, audit.ActionTaskDependencyRemove, taskID, status, status, nil, map[string]any{
+		"blockedBy": blockerID.String(),
+	}); err != nil {
+		return task.Task{}, err
+	}
+
+	tk, err := r.fetchTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Task{}, err
+	}
+	return tk, nil
+}
+
+func attachDependenciesForTask(ctx context.Context, q queryer, tk *task.Task) error {
+	tasks := []task.Task{*tk}
+	if err := attachDependencies(ctx, q, []uuid.UUID{tk.ID}, tasks, map[uuid.UUID]int{tk.ID: 0}); err != nil {
+		return err
+	}
+	*tk = tasks[0]
+	return nil
+}
+
+// attachDependencies 填充任务双向的依赖概要，已删除的任务不计入。
+func attachDependencies(ctx context.Context, q queryer, ids []uuid.UUID, tasks []task.Task, index map[uuid.UUID]int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, 0, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args = append(args, id)
+	}
+	in := strings.Join(placeholders, ", ")
+
+	query := fmt.Sprintf(`
+SELECT d.task_id, d.blocked_by, b.id, b.title, b.status
+FROM task_dependencies d
+JOIN tasks b ON b.id = d.blocked_by
+JOIN tasks o ON o.id = d.task_id
+WHERE d.task_id IN (%[1]s)
+	AND b.deleted_at IS NULL
+	AND o.deleted_at IS NULL
+UNION ALL
+SELECT d.task_id, d.blocked_by, o.id, o.title, o.status
+FROM task_dependencies d
+JOIN tasks o ON o.id = d.task_id
+JOIN tasks b ON b.id = d.blocked_by
+WHERE d.blocked_by IN (%[1]s)
+	AND o.deleted_at IS NULL
+	AND b.deleted_at IS NULL
+ORDER BY 4 ASC
+`, in)
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID    uuid.UUID
+			blockedBy uuid.UUID
+			dep       task.Dependency
+		)
+		if err := rows.Scan(&taskID, &blockedBy, &dep.TaskID, &dep.Title, &dep.Status); err != nil {
+			return err
+		}
+		if dep.TaskID == blockedBy {
+			if idx, ok := index[taskID]; ok {
+				tasks[idx].BlockedBy = append(tasks[idx].BlockedBy, dep)
+			}
+		} else if idx, ok := index[blockedBy]; ok {
+			tasks[idx].Blocks = append(tasks[idx].Blocks, dep)
+		}
+	}
+	return rows.Err()
+}
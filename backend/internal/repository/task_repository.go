@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 
+	"backend/internal/domain/audit"
+	"backend/internal/domain/ledger"
 	"backend/internal/domain/task"
 )
 
@@ -21,6 +24,10 @@ type TaskFilter struct {
 	Limit          int
 	Offset         int
 	AssignedTo     uuid.UUID
+	ParentID       uuid.UUID
+	RootOnly       bool
+	HideBlocked    bool
+	WatchedBy      uuid.UUID
 	IncludeDeleted bool
 }
 
@@ -33,12 +40,20 @@ type TaskCreateInput struct {
 	Priority         task.Priority
 	Deadline         *time.Time
 	CreatedBy        uuid.UUID
+	ParentID         *uuid.UUID
+	RequireChecklist bool
+	Checklist        []string
 	Tags             []string
+	// Publish 为 true 时任务直接以 available 状态创建，并在同一事务内记录发布。
+	Publish bool
+	// ScheduleRun 非空时在同一事务内将该次周期触发标记为已发布；触发已有结果时返回 ErrScheduleRunDone。
+	ScheduleRun *ScheduleRun
 }
 
 // TaskUpdateInput 描述更新任务的字段。
 type TaskUpdateInput struct {
 	ID               uuid.UUID
+	ActorID          uuid.UUID
 	Title            *string
 	DescriptionHTML  *string
 	DescriptionPlain *string
@@ -46,13 +61,50 @@ type TaskUpdateInput struct {
 	Priority         *task.Priority
 	Deadline         *time.Time
 	Status           *task.Status
+	RequireChecklist *bool
 	Tags             *[]string
 }
 
-// TaskAssignmentInput 用于任务领取与释放。
+// TaskAssignmentInput 用于任务领取与释放。UserID 为执行人，ActorID 为操作人（为空时视为执行人本人）。
+// Note 在提交时为执行人的说明，在验收时为审核意见，在自动释放时为释放原因；Links 仅用于提交。
+// System 表示由后台任务触发，审计日志不记录操作人。
 type TaskAssignmentInput struct {
-	TaskID uuid.UUID
-	UserID uuid.UUID
+	TaskID  uuid.UUID
+	UserID  uuid.UUID
+	ActorID uuid.UUID
+	Note    string
+	Links   []string
+	System  bool
+}
+
+// ClaimLimits 为领取时的约束：MaxActive 为同时持有的任务上限，Cooldown 为释放同一任务后的冷却期，0 表示不限制。
+type ClaimLimits struct {
+	MaxActive int
+	Cooldown  time.Duration
+}
+
+var (
+	// ErrClaimLimitReached 表示执行人持有的任务数已达上限。
+	ErrClaimLimitReached = errors.New("repository: claim limit reached")
+	// ErrClaimCooldown 表示执行人释放该任务后仍处于冷却期。
+	ErrClaimCooldown = errors.New("repository: claim cooldown")
+)
+
+// OverdueClaim 描述超过截止时间仍处于领取状态的任务。
+type OverdueClaim struct {
+	TaskID   uuid.UUID
+	UserID   uuid.UUID
+	Deadline time.Time
+}
+
+func (in TaskAssignmentInput) actor() uuid.UUID {
+	if in.System {
+		return uuid.Nil
+	}
+	if in.ActorID != uuid.Nil {
+		return in.ActorID
+	}
+	return in.UserID
 }
 
 // TaskRepository 定义任务相关数据库操作。
@@ -60,14 +112,27 @@ type TaskRepository interface {
 	List(ctx context.Context, filter TaskFilter) ([]task.Task, int, error)
 	Create(ctx context.Context, input TaskCreateInput) (task.Task, error)
 	Update(ctx context.Context, input TaskUpdateInput) (task.Task, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID, actor uuid.UUID) error
 	SetStatus(ctx context.Context, taskID uuid.UUID, status task.Status, actor uuid.UUID) (task.Task, error)
-	Claim(ctx context.Context, input TaskAssignmentInput) (task.Task, error)
+	Claim(ctx context.Context, input TaskAssignmentInput, limits ClaimLimits) (task.Task, error)
 	Release(ctx context.Context, input TaskAssignmentInput) (task.Task, error)
 	Submit(ctx context.Context, input TaskAssignmentInput) (task.Task, error)
 	Reject(ctx context.Context, input TaskAssignmentInput) (task.Task, error)
 	Complete(ctx context.Context, input TaskAssignmentInput) (task.Task, error)
 	GetByID(ctx context.Context, id uuid.UUID) (task.Task, error)
+	ListActivity(ctx context.Context, taskID uuid.UUID) ([]task.Activity, error)
+	AddDependency(ctx context.Context, taskID, blockerID, actor uuid.UUID) (task.Task, error)
+	RemoveDependency(ctx context.Context, taskID, blockerID, actor uuid.UUID) (task.Task, error)
+	AddChecklistItem(ctx context.Context, taskID uuid.UUID, text string, actor uuid.UUID) (task.Task, error)
+	UpdateChecklistItem(ctx context.Context, input ChecklistItemUpdateInput) (task.Task, error)
+	DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID int64, actor uuid.UUID) (task.Task, error)
+	SetChecklistItemDone(ctx context.Context, taskID uuid.UUID, itemID int64, done bool, actor uuid.UUID) (task.Task, error)
+	Watch(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error)
+	Unwatch(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error)
+	ListWatchers(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error)
+	MarkOverdue(ctx context.Context, now time.Time) ([]OverdueClaim, error)
+	ListOverdueClaims(ctx context.Context, cutoff time.Time, limit int) ([]OverdueClaim, error)
+	ListDueSoonClaims(ctx context.Context, from, until time.Time, limit int) ([]OverdueClaim, error)
 }
 
 type taskRepository struct {
@@ -102,6 +167,22 @@ func (r *taskRepository) List(ctx context.Context, filter TaskFilter) ([]task.Ta
 		conditions = append(conditions, fmt.Sprintf("t.status IN (%s)", strings.Join(placeholders, ", ")))
 	}
 
+	if filter.ParentID != uuid.Nil {
+		args = append(args, filter.ParentID)
+		conditions = append(conditions, fmt.Sprintf("t.parent_id = $%d", len(args)))
+	} else if filter.RootOnly {
+		conditions = append(conditions, "t.parent_id IS NULL")
+	}
+
+	if filter.HideBlocked {
+		conditions = append(conditions, "NOT EXISTS ("+openBlockersQuery+")")
+	}
+
+	if filter.WatchedBy != uuid.Nil {
+		args = append(args, filter.WatchedBy)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM task_watchers tw WHERE tw.task_id = t.id AND tw.user_id = $%d)", len(args)))
+	}
+
 	if filter.AssignedTo != uuid.Nil {
 		args = append(args, filter.AssignedTo)
 		placeholder := fmt.Sprintf("$%d", len(args))
@@ -188,6 +269,13 @@ SELECT
 	t.created_at,
 	t.updated_at,
 	t.deleted_at,
+	t.overdue_at,
+	t.parent_id,
+	COALESCE(ch.child_count, 0),
+	COALESCE(ch.child_bounty, 0),
+	t.require_checklist,
+	COALESCE(cl.total, 0),
+	COALESCE(cl.done, 0),
 	la.assignment_id,
 	la.user_id,
 	la.display_name,
@@ -211,10 +299,16 @@ LEFT JOIN LATERAL (
 	ORDER BY ta.created_at DESC
 	LIMIT 1
 ) la ON true
+LEFT JOIN LATERAL (
+%s
+) ch ON true
+LEFT JOIN LATERAL (
+%s
+) cl ON true
 %s
 %s
 LIMIT $%d OFFSET $%d
-`, where, sortClause, len(argsWithPagination)-1, len(argsWithPagination))
+`, childSummaryQuery, checklistSummaryQuery, where, sortClause, len(argsWithPagination)-1, len(argsWithPagination))
 
 	rows, err := r.db.QueryContext(ctx, query, argsWithPagination...)
 	if err != nil {
@@ -232,6 +326,8 @@ LIMIT $%d OFFSET $%d
 			publishedByNull  sql.NullString
 			deadlineNull     sql.NullTime
 			deletedNull      sql.NullTime
+			overdueNull      sql.NullTime
+			parentNull       sql.NullString
 			assignmentID     sql.NullInt64
 			assignmentUser   sql.NullString
 			assignmentName   sql.NullString
@@ -255,6 +351,13 @@ LIMIT $%d OFFSET $%d
 			&tk.CreatedAt,
 			&tk.UpdatedAt,
 			&deletedNull,
+			&overdueNull,
+			&parentNull,
+			&tk.ChildCount,
+			&tk.ChildBounty,
+			&tk.RequireChecklist,
+			&tk.ChecklistTotal,
+			&tk.ChecklistDone,
 			&assignmentID,
 			&assignmentUser,
 			&assignmentName,
@@ -277,6 +380,17 @@ LIMIT $%d OFFSET $%d
 			tk.DeletedAt = &del
 		}
 
+		if overdueNull.Valid {
+			overdue := overdueNull.Time
+			tk.OverdueAt = &overdue
+		}
+
+		if parentNull.Valid {
+			if parentID, err := uuid.Parse(parentNull.String); err == nil {
+				tk.ParentID = &parentID
+			}
+		}
+
 		if publishedByNull.Valid {
 			if pubID, err := uuid.Parse(publishedByNull.String); err == nil {
 				tk.PublishedBy = &pubID
@@ -323,6 +437,9 @@ LIMIT $%d OFFSET $%d
 		if err := r.attachTagsToTasks(ctx, taskIDs, taskList, taskIndex); err != nil {
 			return nil, 0, err
 		}
+		if err := attachDependencies(ctx, r.db, taskIDs, taskList, taskIndex); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	countQuery := "SELECT COUNT(*) FROM tasks t " + where
@@ -347,12 +464,27 @@ func (r *taskRepository) Create(ctx context.Context, input TaskCreateInput) (tas
 	}
 	defer tx.Rollback()
 
+	if input.ParentID != nil {
+		if err := r.checkParentTx(ctx, tx, *input.ParentID); err != nil {
+			return task.Task{}, err
+		}
+	}
+
 	now := time.Now().UTC()
 	id := uuid.New()
 
+	status := task.StatusDraft
+	var publishedBy *uuid.UUID
+	if input.Publish {
+		if status, err = task.Fire(task.StatusDraft, task.EventPublish, task.Facts{}); err != nil {
+			return task.Task{}, err
+		}
+		publishedBy = &input.CreatedBy
+	}
+
 	const insertTask = `
-INSERT INTO tasks (id, title, description_html, description_plain, bounty, priority, status, deadline, created_by, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, 'draft', $7, $8, $9, $9)
+INSERT INTO tasks (id, title, description_html, description_plain, bounty, priority, status, deadline, created_by, published_by, parent_id, require_checklist, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
 RETURNING id, title, description_html, description_plain, bounty, priority, status, deadline, created_by, published_by, created_at, updated_at
 `
 
@@ -368,8 +500,12 @@ RETURNING id, title, description_html, description_plain, bounty, priority, stat
 		input.DescriptionPlain,
 		input.Bounty,
 		input.Priority,
+		status,
 		input.Deadline,
 		input.CreatedBy,
+		publishedBy,
+		input.ParentID,
+		input.RequireChecklist,
 		now,
 	).Scan(
 		&tk.ID,
@@ -398,11 +534,49 @@ RETURNING id, title, description_html, description_plain, bounty, priority, stat
 			tk.PublishedBy = &id
 		}
 	}
+	tk.ParentID = input.ParentID
+	tk.RequireChecklist = input.RequireChecklist
 
 	if err := r.attachTags(ctx, tx, tk.ID, input.Tags); err != nil {
 		return task.Task{}, err
 	}
 
+	for i, text := range input.Checklist {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO task_checklist_items (task_id, position, text, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $4)
+`, tk.ID, i+1, text, now); err != nil {
+			return task.Task{}, err
+		}
+	}
+	tk.ChecklistTotal = len(input.Checklist)
+
+	if err := addWatcherTx(ctx, tx, tk.ID, input.CreatedBy); err != nil {
+		return task.Task{}, err
+	}
+	tk.Watchers = []uuid.UUID{input.CreatedBy}
+
+	createMeta := map[string]any{
+		"title":  tk.Title,
+		"bounty": tk.Bounty,
+	}
+	if input.ParentID != nil {
+		createMeta["parentId"] = input.ParentID.String()
+	}
+	if err := insertTaskAuditTx(ctx, tx, input.CreatedBy, audit.ActionTaskCreate, tk.ID, "", task.StatusDraft, nil, createMeta); err != nil {
+		return task.Task{}, err
+	}
+	if input.Publish {
+		if err := insertTaskAuditTx(ctx, tx, input.CreatedBy, audit.ActionTaskPublish, tk.ID, task.StatusDraft, tk.Status, nil, nil); err != nil {
+			return task.Task{}, err
+		}
+	}
+	if input.ScheduleRun != nil {
+		if err := completeScheduleRunTx(ctx, tx, *input.ScheduleRun, tk.ID); err != nil {
+			return task.Task{}, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -425,6 +599,25 @@ func (r *taskRepository) Update(ctx context.Context, input TaskUpdateInput) (tas
 	}
 	defer tx.Rollback()
 
+	fromStatus, facts, err := r.lockTaskTx(ctx, tx, input.ID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if input.Status != nil && *input.Status != fromStatus {
+		event, err := task.Resolve(fromStatus, *input.Status)
+		if err != nil {
+			return task.Task{}, err
+		}
+		if _, err := task.Fire(fromStatus, event, facts); err != nil {
+			return task.Task{}, err
+		}
+	}
+
+	before, err := r.fetchTaskTx(ctx, tx, input.ID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
 	setParts := make([]string, 0)
 	args := make([]any, 0)
 
@@ -450,12 +643,16 @@ func (r *taskRepository) Update(ctx context.Context, input TaskUpdateInput) (tas
 	}
 	if input.Deadline != nil {
 		args = append(args, *input.Deadline)
-		setParts = append(setParts, fmt.Sprintf("deadline = $%d", len(args)))
+		setParts = append(setParts, fmt.Sprintf("deadline = $%d", len(args)), "overdue_at = NULL")
 	}
 	if input.Status != nil {
 		args = append(args, *input.Status)
 		setParts = append(setParts, fmt.Sprintf("status = $%d", len(args)))
 	}
+	if input.RequireChecklist != nil {
+		args = append(args, *input.RequireChecklist)
+		setParts = append(setParts, fmt.Sprintf("require_checklist = $%d", len(args)))
+	}
 
 	if len(setParts) > 0 {
 		args = append(args, time.Now().UTC(), input.ID)
@@ -490,6 +687,10 @@ WHERE id = $%d
 		return task.Task{}, err
 	}
 
+	if err := insertTaskAuditTx(ctx, tx, input.ActorID, audit.ActionTaskUpdate, tk.ID, before.Status, tk.Status, diffTask(before, tk), nil); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -497,16 +698,27 @@ WHERE id = $%d
 	return tk, nil
 }
 
-func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, id, task.EventDelete)
+	if err != nil {
+		return err
+	}
+
 	now := time.Now().UTC()
-	result, err := r.db.ExecContext(ctx, `
+	result, err := tx.ExecContext(ctx, `
 UPDATE tasks
 SET deleted_at = $2,
-    status = CASE WHEN status = 'archived' THEN status ELSE 'archived' END,
+    status = $3,
     updated_at = $2
 WHERE id = $1
 	AND deleted_at IS NULL
-`, id, now)
+`, id, now, toStatus)
 	if err != nil {
 		return err
 	}
@@ -517,7 +729,12 @@ WHERE id = $1
 	if rows == 0 {
 		return ErrNotFound
 	}
-	return nil
+
+	if err := insertTaskAuditTx(ctx, tx, actor, audit.ActionTaskDelete, id, fromStatus, toStatus, nil, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *taskRepository) SetStatus(ctx context.Context, taskID uuid.UUID, status task.Status, actor uuid.UUID) (task.Task, error) {
@@ -527,6 +744,18 @@ func (r *taskRepository) SetStatus(ctx context.Context, taskID uuid.UUID, status
 	}
 	defer tx.Rollback()
 
+	fromStatus, facts, err := r.lockTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	event, err := task.Resolve(fromStatus, status)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if _, err := task.Fire(fromStatus, event, facts); err != nil {
+		return task.Task{}, err
+	}
+
 	now := time.Now().UTC()
 	const query = `
 UPDATE tasks
@@ -545,6 +774,17 @@ WHERE id = $1
 		return task.Task{}, err
 	}
 
+	action := audit.ActionTaskStatus
+	switch event {
+	case task.EventPublish:
+		action = audit.ActionTaskPublish
+	case task.EventArchive:
+		action = audit.ActionTaskArchive
+	}
+	if err := insertTaskAuditTx(ctx, tx, actor, action, taskID, fromStatus, tk.Status, nil, nil); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -552,35 +792,69 @@ WHERE id = $1
 	return tk, nil
 }
 
-func (r *taskRepository) Claim(ctx context.Context, input TaskAssignmentInput) (task.Task, error) {
+// Claim 领取任务。持有上限与冷却期在同一事务内检查，并先按执行人加事务级咨询锁，
+// 同一成员的并发领取依次执行，不会同时通过检查而超出上限。
+func (r *taskRepository) Claim(ctx context.Context, input TaskAssignmentInput, limits ClaimLimits) (task.Task, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return task.Task{}, err
 	}
 	defer tx.Rollback()
 
-	var currentStatus string
-	if err := tx.QueryRowContext(ctx, `SELECT status FROM tasks WHERE id = $1 AND deleted_at IS NULL`, input.TaskID).Scan(&currentStatus); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return task.Task{}, ErrNotFound
+	now := time.Now().UTC()
+	if limits.MaxActive > 0 || limits.Cooldown > 0 {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('task_claim:' || $1::text, 0))`, input.UserID); err != nil {
+			return task.Task{}, err
 		}
-		return task.Task{}, err
 	}
-	if currentStatus != string(task.StatusAvailable) {
-		return task.Task{}, fmt.Errorf("task not available for claim")
+	if limits.MaxActive > 0 {
+		active, err := countActiveAssignmentsTx(ctx, tx, input.UserID)
+		if err != nil {
+			return task.Task{}, err
+		}
+		if active >= limits.MaxActive {
+			return task.Task{}, fmt.Errorf("%w: holding %d of %d tasks", ErrClaimLimitReached, active, limits.MaxActive)
+		}
+	}
+	if limits.Cooldown > 0 {
+		releasedAt, err := lastReleasedAtTx(ctx, tx, input.TaskID, input.UserID)
+		if err != nil {
+			return task.Task{}, err
+		}
+		if releasedAt != nil {
+			if remaining := releasedAt.Add(limits.Cooldown).Sub(now); remaining > 0 {
+				return task.Task{}, fmt.Errorf("%w: retry in %s", ErrClaimCooldown, remaining.Round(time.Second))
+			}
+		}
+	}
+
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, input.TaskID, task.EventClaim)
+	if errors.Is(err, task.ErrInvalidTransition) {
+		return task.Task{}, fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	if err != nil {
+		return task.Task{}, err
 	}
 
-	now := time.Now().UTC()
-	if _, err := tx.ExecContext(ctx, `
+	var assignmentID int64
+	if err := tx.QueryRowContext(ctx, `
 INSERT INTO task_assignments (task_id, user_id, status, created_at)
 VALUES ($1, $2, 'claimed', $3)
-`, input.TaskID, input.UserID, now); err != nil {
+RETURNING id
+`, input.TaskID, input.UserID, now).Scan(&assignmentID); err != nil {
+		if isUniqueViolation(err) {
+			return task.Task{}, fmt.Errorf("%w: task already has an active assignment", ErrConflict)
+		}
 		return task.Task{}, err
 	}
 
 	if _, err := tx.ExecContext(ctx, `
-UPDATE tasks SET status = 'claimed', updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
-`, input.TaskID, now); err != nil {
+UPDATE tasks SET status = $3, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`, input.TaskID, now, toStatus); err != nil {
+		return task.Task{}, err
+	}
+
+	if err := addWatcherTx(ctx, tx, input.TaskID, input.UserID); err != nil {
 		return task.Task{}, err
 	}
 
@@ -589,6 +863,10 @@ UPDATE tasks SET status = 'claimed', updated_at = $2 WHERE id = $1 AND deleted_a
 		return task.Task{}, err
 	}
 
+	if err := insertTaskAuditTx(ctx, tx, input.actor(), audit.ActionTaskClaim, input.TaskID, fromStatus, tk.Status, nil, assignmentMeta(input.UserID, assignmentID)); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -603,24 +881,30 @@ func (r *taskRepository) Release(ctx context.Context, input TaskAssignmentInput)
 	}
 	defer tx.Rollback()
 
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, input.TaskID, task.EventRelease)
+	if err != nil {
+		return task.Task{}, err
+	}
+
 	now := time.Now().UTC()
-	res, err := tx.ExecContext(ctx, `
+	var assignmentID int64
+	err = tx.QueryRowContext(ctx, `
 UPDATE task_assignments
 SET status = 'released',
 	released_at = $3
 WHERE task_id = $1 AND user_id = $2 AND status = 'claimed'
-`, input.TaskID, input.UserID, now)
+RETURNING id
+`, input.TaskID, input.UserID, now).Scan(&assignmentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, fmt.Errorf("%w: no active claim", ErrConflict)
+	}
 	if err != nil {
 		return task.Task{}, err
 	}
-	affected, _ := res.RowsAffected()
-	if affected == 0 {
-		return task.Task{}, fmt.Errorf("no active claim")
-	}
 
 	if _, err := tx.ExecContext(ctx, `
-UPDATE tasks SET status = 'available', updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
-`, input.TaskID, now); err != nil {
+UPDATE tasks SET status = $3, overdue_at = NULL, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`, input.TaskID, now, toStatus); err != nil {
 		return task.Task{}, err
 	}
 
@@ -629,6 +913,17 @@ UPDATE tasks SET status = 'available', updated_at = $2 WHERE id = $1 AND deleted
 		return task.Task{}, err
 	}
 
+	extra := assignmentMeta(input.UserID, assignmentID)
+	if input.System {
+		extra["automatic"] = true
+	}
+	if input.Note != "" {
+		extra["reason"] = input.Note
+	}
+	if err := insertTaskAuditTx(ctx, tx, input.actor(), audit.ActionTaskRelease, input.TaskID, fromStatus, tk.Status, nil, extra); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -642,24 +937,36 @@ func (r *taskRepository) Submit(ctx context.Context, input TaskAssignmentInput)
 	}
 	defer tx.Rollback()
 
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, input.TaskID, task.EventSubmit)
+	if err != nil {
+		return task.Task{}, err
+	}
+
 	now := time.Now().UTC()
-	res, err := tx.ExecContext(ctx, `
+	var assignmentID int64
+	err = tx.QueryRowContext(ctx, `
 UPDATE task_assignments
 SET status = 'submitted',
 	completed_at = NULL,
 	released_at = NULL
 WHERE task_id = $1 AND user_id = $2 AND status = 'claimed'
-`, input.TaskID, input.UserID)
+RETURNING id
+`, input.TaskID, input.UserID).Scan(&assignmentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, fmt.Errorf("%w: no active claim to submit", ErrConflict)
+	}
 	if err != nil {
 		return task.Task{}, err
 	}
-	if rows, _ := res.RowsAffected(); rows == 0 {
-		return task.Task{}, fmt.Errorf("no active claim to submit")
+
+	submissionID, round, err := insertSubmissionTx(ctx, tx, input.TaskID, assignmentID, input.UserID, input.Note, input.Links, now)
+	if err != nil {
+		return task.Task{}, err
 	}
 
 	if _, err := tx.ExecContext(ctx, `
-UPDATE tasks SET status = 'submitted', updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
-`, input.TaskID, now); err != nil {
+UPDATE tasks SET status = $3, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`, input.TaskID, now, toStatus); err != nil {
 		return task.Task{}, err
 	}
 
@@ -668,6 +975,13 @@ UPDATE tasks SET status = 'submitted', updated_at = $2 WHERE id = $1 AND deleted
 		return task.Task{}, err
 	}
 
+	extra := assignmentMeta(input.UserID, assignmentID)
+	extra["submissionId"] = submissionID
+	extra["round"] = round
+	if err := insertTaskAuditTx(ctx, tx, input.actor(), audit.ActionTaskSubmit, input.TaskID, fromStatus, tk.Status, nil, extra); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -682,24 +996,36 @@ func (r *taskRepository) Reject(ctx context.Context, input TaskAssignmentInput)
 	}
 	defer tx.Rollback()
 
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, input.TaskID, task.EventReject)
+	if err != nil {
+		return task.Task{}, err
+	}
+
 	now := time.Now().UTC()
-	res, err := tx.ExecContext(ctx, `
+	var assignmentID int64
+	err = tx.QueryRowContext(ctx, `
 UPDATE task_assignments
 SET status = 'claimed',
 	completed_at = NULL,
 	released_at = NULL
 WHERE task_id = $1 AND user_id = $2 AND status = 'submitted'
-`, input.TaskID, input.UserID)
+RETURNING id
+`, input.TaskID, input.UserID).Scan(&assignmentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, fmt.Errorf("%w: no submission to reject", ErrConflict)
+	}
 	if err != nil {
 		return task.Task{}, err
 	}
-	if rows, _ := res.RowsAffected(); rows == 0 {
-		return task.Task{}, fmt.Errorf("no submission to reject")
+
+	submissionID, err := reviewSubmissionTx(ctx, tx, assignmentID, task.VerdictRejected, input.actor(), input.Note, now)
+	if err != nil {
+		return task.Task{}, err
 	}
 
 	if _, err := tx.ExecContext(ctx, `
-UPDATE tasks SET status = 'claimed', updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
-`, input.TaskID, now); err != nil {
+UPDATE tasks SET status = $3, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
+`, input.TaskID, now, toStatus); err != nil {
 		return task.Task{}, err
 	}
 
@@ -708,6 +1034,17 @@ UPDATE tasks SET status = 'claimed', updated_at = $2 WHERE id = $1 AND deleted_a
 		return task.Task{}, err
 	}
 
+	extra := assignmentMeta(input.UserID, assignmentID)
+	if submissionID > 0 {
+		extra["submissionId"] = submissionID
+	}
+	if input.Note != "" {
+		extra["comment"] = input.Note
+	}
+	if err := insertTaskAuditTx(ctx, tx, input.actor(), audit.ActionTaskReject, input.TaskID, fromStatus, tk.Status, nil, extra); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
@@ -722,37 +1059,114 @@ func (r *taskRepository) Complete(ctx context.Context, input TaskAssignmentInput
 	}
 	defer tx.Rollback()
 
+	fromStatus, toStatus, err := r.transitionTx(ctx, tx, input.TaskID, task.EventComplete)
+	if err != nil {
+		return task.Task{}, err
+	}
+
 	now := time.Now().UTC()
-	res, err := tx.ExecContext(ctx, `
+	var assignmentID int64
+	err = tx.QueryRowContext(ctx, `
 UPDATE task_assignments
 SET status = 'completed',
 	completed_at = $3
 WHERE task_id = $1 AND user_id = $2 AND status = 'submitted'
-`, input.TaskID, input.UserID, now)
+RETURNING id
+`, input.TaskID, input.UserID, now).Scan(&assignmentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, fmt.Errorf("%w: no submission to approve", ErrConflict)
+	}
 	if err != nil {
 		return task.Task{}, err
 	}
-	if rows, _ := res.RowsAffected(); rows == 0 {
-		return task.Task{}, fmt.Errorf("no submission to approve")
+
+	submissionID, err := reviewSubmissionTx(ctx, tx, assignmentID, task.VerdictApproved, input.actor(), input.Note, now)
+	if err != nil {
+		return task.Task{}, err
 	}
 
-	if _, err := tx.ExecContext(ctx, `
-UPDATE tasks SET status = 'completed', updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
-`, input.TaskID, now); err != nil {
+	var bounty int64
+	err = tx.QueryRowContext(ctx, `
+UPDATE tasks SET status = $3, updated_at = $2 WHERE id = $1 AND deleted_at IS NULL
+RETURNING bounty
+`, input.TaskID, now, toStatus).Scan(&bounty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Task{}, ErrNotFound
+	}
+	if err != nil {
 		return task.Task{}, err
 	}
 
+	if bounty > 0 {
+		taskID := input.TaskID
+		if err := insertLedgerEntryTx(ctx, tx, ledger.Entry{
+			UserID:       input.UserID,
+			Kind:         ledger.KindTaskReward,
+			Amount:       bounty,
+			TaskID:       &taskID,
+			AssignmentID: &assignmentID,
+			CreatedAt:    now,
+		}); err != nil {
+			return task.Task{}, err
+		}
+	}
+
 	tk, err := r.fetchTaskTx(ctx, tx, input.TaskID)
 	if err != nil {
 		return task.Task{}, err
 	}
 
+	extra := assignmentMeta(input.UserID, assignmentID)
+	extra["bounty"] = bounty
+	if submissionID > 0 {
+		extra["submissionId"] = submissionID
+	}
+	if input.Note != "" {
+		extra["comment"] = input.Note
+	}
+	if err := insertTaskAuditTx(ctx, tx, input.actor(), audit.ActionTaskComplete, input.TaskID, fromStatus, tk.Status, nil, extra); err != nil {
+		return task.Task{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return task.Task{}, err
 	}
 	return tk, nil
 }
 
+func countActiveAssignmentsTx(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (int, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, `
+SELECT COUNT(*)
+FROM task_assignments ta
+JOIN tasks t ON t.id = ta.task_id
+WHERE ta.user_id = $1
+	AND ta.status IN ('claimed', 'submitted')
+	AND t.deleted_at IS NULL
+`, userID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func lastReleasedAtTx(ctx context.Context, tx *sql.Tx, taskID, userID uuid.UUID) (*time.Time, error) {
+	var releasedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+SELECT MAX(released_at)
+FROM task_assignments
+WHERE task_id = $1 AND user_id = $2 AND status = 'released'
+`, taskID, userID).Scan(&releasedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !releasedAt.Valid {
+		return nil, nil
+	}
+	t := releasedAt.Time
+	return &t, nil
+}
+
 func (r *taskRepository) GetByID(ctx context.Context, id uuid.UUID) (task.Task, error) {
 	return r.fetchTask(ctx, id)
 }
@@ -789,8 +1203,21 @@ SELECT
 	t.published_by,
 	t.created_at,
 	t.updated_at,
-	t.deleted_at
+	t.deleted_at,
+	t.overdue_at,
+	t.parent_id,
+	COALESCE(ch.child_count, 0),
+	COALESCE(ch.child_bounty, 0),
+	t.require_checklist,
+	COALESCE(cl.total, 0),
+	COALESCE(cl.done, 0)
 FROM tasks t
+LEFT JOIN LATERAL (
+` + childSummaryQuery + `
+) ch ON true
+LEFT JOIN LATERAL (
+` + checklistSummaryQuery + `
+) cl ON true
 WHERE t.id = $1
 	AND t.deleted_at IS NULL
 `
@@ -799,6 +1226,8 @@ WHERE t.id = $1
 		deadlineNull sql.NullTime
 		pubNull      sql.NullString
 		deletedNull  sql.NullTime
+		overdueNull  sql.NullTime
+		parentNull   sql.NullString
 	)
 	err := tx.QueryRowContext(ctx, query, id).Scan(
 		&tk.ID,
@@ -814,6 +1243,13 @@ WHERE t.id = $1
 		&tk.CreatedAt,
 		&tk.UpdatedAt,
 		&deletedNull,
+		&overdueNull,
+		&parentNull,
+		&tk.ChildCount,
+		&tk.ChildBounty,
+		&tk.RequireChecklist,
+		&tk.ChecklistTotal,
+		&tk.ChecklistDone,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return task.Task{}, ErrNotFound
@@ -835,11 +1271,36 @@ WHERE t.id = $1
 		del := deletedNull.Time
 		tk.DeletedAt = &del
 	}
+	if overdueNull.Valid {
+		overdue := overdueNull.Time
+		tk.OverdueAt = &overdue
+	}
+	if parentNull.Valid {
+		if parentID, err := uuid.Parse(parentNull.String); err == nil {
+			tk.ParentID = &parentID
+		}
+	}
+
+	if err := attachDependenciesForTask(ctx, tx, &tk); err != nil {
+		return task.Task{}, err
+	}
+
+	if err := attachChecklistTx(ctx, tx, &tk); err != nil {
+		return task.Task{}, err
+	}
+
+	if tk.Watchers, err = listWatchers(ctx, tx, tk.ID); err != nil {
+		return task.Task{}, err
+	}
 
 	if err := r.attachTagsForTask(ctx, tx, &tk); err != nil {
 		return task.Task{}, err
 	}
 
+	if err := r.attachSubmissionsTx(ctx, tx, &tk); err != nil {
+		return task.Task{}, err
+	}
+
 	const assignmentQuery = `
 SELECT ta.id, ta.user_id, u.display_name, ta.status, ta.created_at, ta.completed_at, ta.released_at
 FROM task_assignments ta
@@ -995,3 +1456,100 @@ ORDER BY tt.name ASC
 	}
 	return rows.Err()
 }
+
+// lockTaskTx 锁定任务行并读取状态机守卫所需的事实，并发修改同一任务时后到的事务会等待前者提交。
+func (r *taskRepository) lockTaskTx(ctx context.Context, tx *sql.Tx, id uuid.UUID) (task.Status, task.Facts, error) {
+	var (
+		status task.Status
+		facts  task.Facts
+	)
+	err := tx.QueryRowContext(ctx, `
+SELECT
+	t.status,
+	EXISTS (
+		SELECT 1 FROM task_assignments ta
+		WHERE ta.task_id = t.id AND ta.status IN ('claimed', 'submitted')
+	),
+	(
+		SELECT COUNT(*) FROM tasks c
+		WHERE c.parent_id = t.id
+			AND c.deleted_at IS NULL
+			AND c.status NOT IN ('completed', 'archived')
+	),
+	(
+		SELECT COUNT(*) FROM (`+openBlockersQuery+`) ob
+	),
+	t.require_checklist,
+	(
+		SELECT COUNT(*) FROM task_checklist_items ci
+		WHERE ci.task_id = t.id AND NOT ci.done
+	)
+FROM tasks t
+WHERE t.id = $1 AND t.deleted_at IS NULL
+FOR UPDATE OF t
+`, id).Scan(&status, &facts.ActiveAssignment, &facts.OpenChildren, &facts.OpenBlockers, &facts.RequireChecklist, &facts.OpenChecklistItems)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", task.Facts{}, ErrNotFound
+	}
+	if err != nil {
+		return "", task.Facts{}, err
+	}
+	return status, facts, nil
+}
+
+// transitionTx 按状态机校验事件，返回转换前后的状态。
+func (r *taskRepository) transitionTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, event task.Event) (task.Status, task.Status, error) {
+	from, facts, err := r.lockTaskTx(ctx, tx, id)
+	if err != nil {
+		return "", "", err
+	}
+	to, err := task.Fire(from, event, facts)
+	if err != nil {
+		return "", "", err
+	}
+	return from, to, nil
+}
+
+// childSummaryQuery 统计未删除子任务的数量与赏金合计，供列表与详情查询 LATERAL 关联。
+const childSummaryQuery = `
+	SELECT COUNT(*) AS child_count, SUM(c.bounty) AS child_bounty
+	FROM tasks c
+	WHERE c.parent_id = t.id
+		AND c.deleted_at IS NULL`
+
+// checkParentTx 校验父任务存在、仍可拆分且自身不是子任务（层级仅一层）。
+func (r *taskRepository) checkParentTx(ctx context.Context, tx *sql.Tx, parentID uuid.UUID) error {
+	var (
+		status      task.Status
+		grandparent sql.NullString
+	)
+	err := tx.QueryRowContext(ctx, `
+SELECT status, parent_id FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR SHARE
+`, parentID).Scan(&status, &grandparent)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if grandparent.Valid {
+		return fmt.Errorf("%w: subtasks cannot have children", ErrConflict)
+	}
+	if status == task.StatusCompleted || status == task.StatusArchived {
+		return fmt.Errorf("%w: parent task is %s", ErrConflict, status)
+	}
+	return nil
+}
+
+// isUniqueViolation 判断错误是否为唯一约束冲突。
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func assignmentMeta(assignee uuid.UUID, assignmentID int64) map[string]any {
+	return map[string]any{
+		"assigneeId":   assignee.String(),
+		"assignmentId": assignmentID,
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+)
+
+// insertSubmissionTx 写入新一轮提交记录，轮次按任务累计。
+func insertSubmissionTx(ctx context.Context, tx *sql.Tx, taskID uuid.UUID, assignmentID int64, submittedBy uuid.UUID, note string, links []string, now time.Time) (int64, int, error) {
+	if links == nil {
+		links = []string{}
+	}
+	linksRaw, err := json.Marshal(links)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		id    int64
+		round int
+	)
+	err = tx.QueryRowContext(ctx, `
+INSERT INTO task_submissions (task_id, assignment_id, round, submitted_by, note, links, submitted_at)
+VALUES (
+	$1,
+	$2,
+	(SELECT COALESCE(MAX(round), 0) + 1 FROM task_submissions WHERE task_id = $1),
+	$3,
+	$4,
+	$5,
+	$6
+)
+RETURNING id, round
+`, taskID, assignmentID, submittedBy, note, string(linksRaw), now).Scan(&id, &round)
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, round, nil
+}
+
+// reviewSubmissionTx 为该领取记录最近一轮待审核提交写入结论；旧数据没有提交记录时返回 0。
+func reviewSubmissionTx(ctx context.Context, tx *sql.Tx, assignmentID int64, verdict task.Verdict, reviewer uuid.UUID, comment string, now time.Time) (int64, error) {
+	var id int64
+	err := tx.QueryRowContext(ctx, `
+UPDATE task_submissions
+SET verdict = $2,
+	reviewer_id = $3,
+	review_comment = NULLIF($4, ''),
+	reviewed_at = $5
+WHERE id = (
+	SELECT id FROM task_submissions
+	WHERE assignment_id = $1 AND verdict = 'pending'
+	ORDER BY round DESC
+	LIMIT 1
+)
+RETURNING id
+`, assignmentID, string(verdict), reviewer, comment, now).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *taskRepository) attachSubmissionsTx(ctx context.Context, tx *sql.Tx, tk *task.Task) error {
+	rows, err := tx.QueryContext(ctx, `
+SELECT
+	s.id,
+	s.assignment_id,
+	s.round,
+	s.submitted_by,
+	COALESCE(su.display_name, ''),
+	s.note,
+	s.links,
+	s.submitted_at,
+	s.verdict,
+	s.reviewer_id,
+	COALESCE(ru.display_name, ''),
+	COALESCE(s.review_comment, ''),
+	s.reviewed_at
+FROM task_submissions s
+LEFT JOIN users su ON su.id = s.submitted_by
+LEFT JOIN users ru ON ru.id = s.reviewer_id
+WHERE s.task_id = $1
+ORDER BY s.round ASC
+`, tk.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tk.Submissions = make([]task.Submission, 0)
+	for rows.Next() {
+		var (
+			sub        task.Submission
+			linksRaw   []byte
+			reviewerID sql.NullString
+			reviewedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&sub.ID,
+			&sub.AssignmentID,
+			&sub.Round,
+			&sub.SubmittedBy,
+			&sub.SubmitterName,
+			&sub.Note,
+			&linksRaw,
+			&sub.SubmittedAt,
+			&sub.Verdict,
+			&reviewerID,
+			&sub.ReviewerName,
+			&sub.ReviewComment,
+			&reviewedAt,
+		); err != nil {
+			return err
+		}
+		sub.TaskID = tk.ID
+		if len(linksRaw) > 0 {
+			if err := json.Unmarshal(linksRaw, &sub.Links); err != nil {
+				return err
+			}
+		}
+		if reviewerID.Valid {
+			if id, err := uuid.Parse(reviewerID.String); err == nil {
+				sub.ReviewerID = &id
+			}
+		}
+		if reviewedAt.Valid {
+			t := reviewedAt.Time
+			sub.ReviewedAt = &t
+		}
+		tk.Submissions = append(tk.Submissions, sub)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+)
+
+// Watch 将用户加入任务关注者，重复关注视为成功。
+func (r *taskRepository) Watch(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureTaskExistsTx(ctx, tx, taskID); err != nil {
+		return task.Task{}, err
+	}
+	if err := addWatcherTx(ctx, tx, taskID, userID); err != nil {
+		return task.Task{}, err
+	}
+
+	tk, err := r.fetchTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Task{}, err
+	}
+	return tk, nil
+}
+
+// Unwatch 取消关注，未关注时同样视为成功。
+func (r *taskRepository) Unwatch(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Task{}, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureTaskExistsTx(ctx, tx, taskID); err != nil {
+		return task.Task{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2
+`, taskID, userID); err != nil {
+		return task.Task{}, err
+	}
+
+	tk, err := r.fetchTaskTx(ctx, tx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return task.Task{}, err
+	}
+	return tk, nil
+}
+
+// ListWatchers 返回任务的全部关注者，供通知扇出使用，已停用账号不计入。
+func (r *taskRepository) ListWatchers(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error) {
+	return listWatchers(ctx, r.db, taskID)
+}
+
+func ensureTaskExistsTx(ctx context.Context, tx *sql.Tx, taskID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `
+SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL
+`, taskID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func addWatcherTx(ctx context.Context, tx *sql.Tx, taskID, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO task_watchers (task_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`, taskID, userID)
+	return err
+}
+
+func listWatchers(ctx context.Context, q queryer, taskID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.QueryContext(ctx, `
+SELECT w.user_id
+FROM task_watchers w
+JOIN users u ON u.id = w.user_id
+WHERE w.task_id = $1 AND u.status = 'active'
+ORDER BY w.created_at ASC
+`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchers := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		watchers = append(watchers, id)
+	}
+	return watchers, rows.Err()
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/task"
+)
+
+// TemplateRepository 定义任务模板相关数据库操作。
+type TemplateRepository interface {
+	List(ctx context.Context) ([]task.Template, error)
+	GetByID(ctx context.Context, id uuid.UUID) (task.Template, error)
+	Create(ctx context.Context, tpl task.Template) (task.Template, error)
+	Update(ctx context.Context, tpl task.Template, actor uuid.UUID) (task.Template, error)
+	Delete(ctx context.Context, id, actor uuid.UUID) error
+}
+
+type templateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository 构造任务模板仓储。
+func NewTemplateRepository(db *sql.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+const templateColumns = `id, title, description_html, bounty, priority, tags, deadline_offset_minutes, created_by, created_at, updated_at`
+
+func (r *templateRepository) List(ctx context.Context) ([]task.Template, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+templateColumns+` FROM task_templates ORDER BY title ASC, created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]task.Template, 0)
+	for rows.Next() {
+		tpl, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tpl)
+	}
+	return templates, rows.Err()
+}
+
+func (r *templateRepository) GetByID(ctx context.Context, id uuid.UUID) (task.Template, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+templateColumns+` FROM task_templates WHERE id = $1`, id)
+	tpl, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Template{}, ErrNotFound
+	}
+	return tpl, err
+}
+
+func (r *templateRepository) Create(ctx context.Context, tpl task.Template) (task.Template, error) {
+	tagsRaw, err := marshalTags(tpl.Tags)
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Template{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	row := tx.QueryRowContext(ctx, `
+INSERT INTO task_templates (id, title, description_html, bounty, priority, tags, deadline_offset_minutes, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+RETURNING `+templateColumns,
+		uuid.New(),
+		tpl.Title,
+		tpl.DescriptionHTML,
+		tpl.Bounty,
+		string(tpl.Priority),
+		tagsRaw,
+		int(tpl.DeadlineOffset/time.Minute),
+		tpl.CreatedBy,
+		now,
+	)
+	created, err := scanTemplate(row)
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    tpl.CreatedBy,
+		Action:     audit.ActionTemplateCreate,
+		Resource:   "task_template",
+		ResourceID: created.ID.String(),
+		Metadata:   map[string]any{"title": created.Title},
+		CreatedAt:  now,
+	}); err != nil {
+		return task.Template{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task.Template{}, err
+	}
+	return created, nil
+}
+
+func (r *templateRepository) Update(ctx context.Context, tpl task.Template, actor uuid.UUID) (task.Template, error) {
+	tagsRaw, err := marshalTags(tpl.Tags)
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return task.Template{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	row := tx.QueryRowContext(ctx, `
+UPDATE task_templates
+SET title = $2,
+	description_html = $3,
+	bounty = $4,
+	priority = $5,
+	tags = $6,
+	deadline_offset_minutes = $7,
+	updated_at = $8
+WHERE id = $1
+RETURNING `+templateColumns,
+		tpl.ID,
+		tpl.Title,
+		tpl.DescriptionHTML,
+		tpl.Bounty,
+		string(tpl.Priority),
+		tagsRaw,
+		int(tpl.DeadlineOffset/time.Minute),
+		now,
+	)
+	updated, err := scanTemplate(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return task.Template{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionTemplateUpdate,
+		Resource:   "task_template",
+		ResourceID: updated.ID.String(),
+		Metadata:   map[string]any{"title": updated.Title},
+		CreatedAt:  now,
+	}); err != nil {
+		return task.Template{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return task.Template{}, err
+	}
+	return updated, nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var title string
+	err = tx.QueryRowContext(ctx, `DELETE FROM task_templates WHERE id = $1 RETURNING title`, id).Scan(&title)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionTemplateDelete,
+		Resource:   "task_template",
+		ResourceID: id.String(),
+		Metadata:   map[string]any{"title": title},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTemplate(row rowScanner) (task.Template, error) {
+	var (
+		tpl           task.Template
+		tagsRaw       []byte
+		offsetMinutes int
+	)
+	if err := row.Scan(
+		&tpl.ID,
+		&tpl.Title,
+		&tpl.DescriptionHTML,
+		&tpl.Bounty,
+		&tpl.Priority,
+		&tagsRaw,
+		&offsetMinutes,
+		&tpl.CreatedBy,
+		&tpl.CreatedAt,
+		&tpl.UpdatedAt,
+	); err != nil {
+		return task.Template{}, err
+	}
+	tpl.Tags = make([]string, 0)
+	if len(tagsRaw) > 0 {
+		if err := json.Unmarshal(tagsRaw, &tpl.Tags); err != nil {
+			return task.Template{}, err
+		}
+	}
+	tpl.DeadlineOffset = time.Duration(offsetMinutes) * time.Minute
+	return tpl, nil
+}
+
+func marshalTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	raw, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
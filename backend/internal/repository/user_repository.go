@@ -11,11 +11,16 @@ import (
 
 	"github.com/google/uuid"
 
+	"backend/internal/domain/audit"
 	"backend/internal/domain/user"
 )
 
-// ErrNotFound 表示未找到记录。
-var ErrNotFound = errors.New("repository: not found")
+var (
+	// ErrNotFound 表示未找到记录。
+	ErrNotFound = errors.New("repository: not found")
+	// ErrConflict 表示记录已被并发修改或当前状态不允许该操作。
+	ErrConflict = errors.New("repository: conflict")
+)
 
 // Credential 包含登录凭据字段。
 type Credential struct {
@@ -35,6 +40,7 @@ type UserRepository interface {
 	RecordLogin(ctx context.Context, userID uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (user.User, error)
 	UpdateProfile(ctx context.Context, id uuid.UUID, displayName, headline, bio string) (user.User, error)
+	UpdateEmailSettings(ctx context.Context, id uuid.UUID, email *string, mode user.EmailMode) (user.User, error)
 	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash, algorithm string, cost int) error
 	ListUsers(ctx context.Context, keyword string, limit, offset int) ([]user.User, int, error)
 	ToggleRole(ctx context.Context, targetID, operatorID uuid.UUID, role user.Role, grant bool) error
@@ -197,6 +203,7 @@ SELECT
 	u.username,
 	u.display_name,
 	COALESCE(u.email, ''),
+	u.email_mode,
 	COALESCE(u.headline, ''),
 	COALESCE(u.bio, ''),
 	COALESCE(u.avatar_url, ''),
@@ -222,6 +229,7 @@ WHERE u.id = $1
 		&u.Username,
 		&u.DisplayName,
 		&u.Email,
+		&u.EmailMode,
 		&u.Headline,
 		&u.Bio,
 		&u.AvatarURL,
@@ -259,6 +267,7 @@ RETURNING
 	username,
 	display_name,
 	COALESCE(email, ''),
+	email_mode,
 	COALESCE(headline, ''),
 	COALESCE(bio, ''),
 	COALESCE(avatar_url, ''),
@@ -275,6 +284,7 @@ RETURNING
 		&u.Username,
 		&u.DisplayName,
 		&u.Email,
+		&u.EmailMode,
 		&u.Headline,
 		&u.Bio,
 		&u.AvatarURL,
@@ -298,6 +308,30 @@ RETURNING
 	return u, nil
 }
 
+// UpdateEmailSettings 更新通知邮箱与发送方式，email 为 nil 表示清空邮箱；邮箱已被其他用户使用时返回 ErrConflict。
+func (r *userRepository) UpdateEmailSettings(ctx context.Context, id uuid.UUID, email *string, mode user.EmailMode) (user.User, error) {
+	const query = `
+UPDATE users
+SET email = $2,
+	email_mode = $3,
+	updated_at = $4
+WHERE id = $1
+RETURNING id
+`
+	var updatedID uuid.UUID
+	err := r.db.QueryRowContext(ctx, query, id, email, string(mode), time.Now().UTC()).Scan(&updatedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user.User{}, ErrNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return user.User{}, ErrConflict
+		}
+		return user.User{}, err
+	}
+	return r.GetByID(ctx, updatedID)
+}
+
 func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash, algorithm string, cost int) error {
 	const query = `
 UPDATE user_credentials
@@ -446,16 +480,17 @@ WHERE user_id = $1 AND role_key = $2
 		}
 	}
 
-	const audit = `
-INSERT INTO audit_logs (user_id, action, resource, resource_id, metadata, created_at)
-VALUES ($1, $2, 'user', $3, $4, $5)
-`
-	action := "role_revoke"
+	action := audit.ActionRoleRevoke
 	if grant {
-		action = "role_grant"
-	}
-	meta := fmt.Sprintf(`{"role":"%s"}`, role)
-	if _, err := tx.ExecContext(ctx, audit, operatorID, action, targetID.String(), meta, time.Now().UTC()); err != nil {
+		action = audit.ActionRoleGrant
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    operatorID,
+		Action:     action,
+		Resource:   "user",
+		ResourceID: targetID.String(),
+		Metadata:   map[string]any{"role": string(role)},
+	}); err != nil {
 		return err
 	}
 
@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/domain/webhook"
+)
+
+// WebhookRepository 定义外部 webhook 及其投递记录相关数据库操作。
+type WebhookRepository interface {
+	List(ctx context.Context) ([]webhook.Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (webhook.Webhook, error)
+	Create(ctx context.Context, hook webhook.Webhook) (webhook.Webhook, error)
+	Update(ctx context.Context, hook webhook.Webhook, actor uuid.UUID) (webhook.Webhook, error)
+	Delete(ctx context.Context, id, actor uuid.UUID) error
+	Enqueue(ctx context.Context, eventID, eventType string, payload []byte, at time.Time) (int, error)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status webhook.DeliveryStatus, limit, offset int) ([]webhook.Delivery, int, error)
+	GetDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (webhook.Delivery, error)
+	Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID int64, actor uuid.UUID) (webhook.Delivery, error)
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]DueDelivery, error)
+	RecordAttempt(ctx context.Context, input WebhookAttemptInput) error
+}
+
+// DueDelivery 是一条已被当前副本租用、待发送的投递，附带目标地址与签名密钥。
+type DueDelivery struct {
+	Delivery webhook.Delivery
+	URL      string
+	Secret   string
+}
+
+// WebhookAttemptInput 描述一次投递尝试的结果及投递的后续状态，NextAttemptAt 仅在继续重试时设置。
+type WebhookAttemptInput struct {
+	DeliveryID    int64
+	StatusCode    int
+	Error         string
+	ResponseBody  string
+	Duration      time.Duration
+	Status        webhook.DeliveryStatus
+	NextAttemptAt *time.Time
+	At            time.Time
+}
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository 构造 webhook 仓储。
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, secret, events, description, active, created_by, created_at, updated_at`
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_status_code, d.last_error, d.redelivery_of, d.created_at, d.delivered_at`
+
+func (r *webhookRepository) List(ctx context.Context) ([]webhook.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := make([]webhook.Webhook, 0)
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (webhook.Webhook, error) {
+	hook, err := scanWebhook(r.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook.Webhook{}, ErrNotFound
+	}
+	return hook, err
+}
+
+func (r *webhookRepository) Create(ctx context.Context, hook webhook.Webhook) (webhook.Webhook, error) {
+	eventsRaw, err := marshalTags(hook.Events)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	created, err := scanWebhook(tx.QueryRowContext(ctx, `
+INSERT INTO webhooks (id, url, secret, events, description, active, created_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+RETURNING `+webhookColumns,
+		uuid.New(),
+		hook.URL,
+		hook.Secret,
+		eventsRaw,
+		hook.Description,
+		hook.Active,
+		hook.CreatedBy,
+		now,
+	))
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    hook.CreatedBy,
+		Action:     audit.ActionWebhookCreate,
+		Resource:   "webhook",
+		ResourceID: created.ID.String(),
+		Metadata:   map[string]any{"url": created.URL, "events": created.Events, "active": created.Active},
+		CreatedAt:  now,
+	}); err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return webhook.Webhook{}, err
+	}
+	return created, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, hook webhook.Webhook, actor uuid.UUID) (webhook.Webhook, error) {
+	eventsRaw, err := marshalTags(hook.Events)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanWebhook(tx.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1 FOR UPDATE`, hook.ID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook.Webhook{}, ErrNotFound
+	}
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	now := time.Now().UTC()
+	updated, err := scanWebhook(tx.QueryRowContext(ctx, `
+UPDATE webhooks
+SET url = $2,
+	secret = $3,
+	events = $4,
+	description = $5,
+	active = $6,
+	updated_at = $7
+WHERE id = $1
+RETURNING `+webhookColumns,
+		hook.ID,
+		hook.URL,
+		hook.Secret,
+		eventsRaw,
+		hook.Description,
+		hook.Active,
+		now,
+	))
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	// 密钥只记录是否轮换，不写入审计日志。
+	changes := make(map[string]any)
+	if before.URL != updated.URL {
+		changes["url"] = fieldChange(before.URL, updated.URL)
+	}
+	if !slices.Equal(before.Events, updated.Events) {
+		changes["events"] = fieldChange(before.Events, updated.Events)
+	}
+	if before.Description != updated.Description {
+		changes["description"] = fieldChange(before.Description, updated.Description)
+	}
+	if before.Active != updated.Active {
+		changes["active"] = fieldChange(before.Active, updated.Active)
+	}
+	meta := map[string]any{"url": updated.URL, "changes": changes}
+	if before.Secret != updated.Secret {
+		meta["secretRotated"] = true
+	}
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionWebhookUpdate,
+		Resource:   "webhook",
+		ResourceID: updated.ID.String(),
+		Metadata:   meta,
+		CreatedAt:  now,
+	}); err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return webhook.Webhook{}, err
+	}
+	return updated, nil
+}
+
+// Delete 删除 webhook，其投递记录随之级联删除。
+func (r *webhookRepository) Delete(ctx context.Context, id, actor uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var url string
+	err = tx.QueryRowContext(ctx, `DELETE FROM webhooks WHERE id = $1 RETURNING url`, id).Scan(&url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionWebhookDelete,
+		Resource:   "webhook",
+		ResourceID: id.String(),
+		Metadata:   map[string]any{"url": url},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Enqueue 为每个订阅了该事件的启用中 webhook 创建一条待投递记录，同一事件重复写入会被忽略，返回创建数量。
+func (r *webhookRepository) Enqueue(ctx context.Context, eventID, eventType string, payload []byte, at time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at, created_at)
+SELECT w.id, $1, $2, $3, $4, $4
+FROM webhooks w
+WHERE w.active AND (w.events = '[]'::jsonb OR w.events ? $2)
+ON CONFLICT DO NOTHING
+`, eventID, eventType, string(payload), at)
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := res.RowsAffected()
+	return int(affected), nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status webhook.DeliveryStatus, limit, offset int) ([]webhook.Delivery, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+deliveryColumns+`
+FROM webhook_deliveries d
+WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT $3 OFFSET $4
+`, webhookID, string(status), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]webhook.Delivery, 0)
+	for rows.Next() {
+		item, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+`, webhookID, string(status)).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// GetDelivery 返回投递详情及全部尝试记录。
+func (r *webhookRepository) GetDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (webhook.Delivery, error) {
+	item, err := scanDelivery(r.db.QueryRowContext(ctx, `
+SELECT `+deliveryColumns+` FROM webhook_deliveries d WHERE d.id = $1 AND d.webhook_id = $2
+`, deliveryID, webhookID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook.Delivery{}, ErrNotFound
+	}
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt ASC, id ASC
+`, deliveryID)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	defer rows.Close()
+
+	item.History = make([]webhook.Attempt, 0)
+	for rows.Next() {
+		var (
+			attempt    webhook.Attempt
+			statusCode sql.NullInt64
+			durationMS int64
+		)
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.DeliveryID,
+			&attempt.Attempt,
+			&statusCode,
+			&attempt.Error,
+			&attempt.ResponseBody,
+			&durationMS,
+			&attempt.CreatedAt,
+		); err != nil {
+			return webhook.Delivery{}, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			attempt.StatusCode = &code
+		}
+		attempt.Duration = time.Duration(durationMS) * time.Millisecond
+		item.History = append(item.History, attempt)
+	}
+	return item, rows.Err()
+}
+
+// Redeliver 复制原投递的事件内容生成一条新的待投递记录，原记录保持不变以便追溯。
+func (r *webhookRepository) Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID int64, actor uuid.UUID) (webhook.Delivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	created, err := scanDelivery(tx.QueryRowContext(ctx, `
+INSERT INTO webhook_deliveries AS d (webhook_id, event_id, event_type, payload, next_attempt_at, redelivery_of, created_at)
+SELECT src.webhook_id, src.event_id, src.event_type, src.payload, $3, src.id, $3
+FROM webhook_deliveries src
+WHERE src.id = $1 AND src.webhook_id = $2
+RETURNING `+deliveryColumns,
+		deliveryID,
+		webhookID,
+		now,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return webhook.Delivery{}, ErrNotFound
+	}
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	if err := insertAuditLogTx(ctx, tx, auditEntry{
+		ActorID:    actor,
+		Action:     audit.ActionWebhookRedeliver,
+		Resource:   "webhook",
+		ResourceID: webhookID.String(),
+		Metadata:   map[string]any{"deliveryId": deliveryID, "newDeliveryId": created.ID, "eventType": created.EventType},
+		CreatedAt:  now,
+	}); err != nil {
+		return webhook.Delivery{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return webhook.Delivery{}, err
+	}
+	return created, nil
+}
+
+// ClaimDueDeliveries 租用到期的投递：将 next_attempt_at 推迟到 leaseUntil，
+// 多副本并发巡检时借助 SKIP LOCKED 各自领取不同的记录；进程中途退出时租约到期后会被重新领取。
+// webhook 被停用后其待投递记录暂不发送，重新启用后继续。
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]DueDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+WITH due AS (
+	SELECT d.id
+	FROM webhook_deliveries d
+	JOIN webhooks w ON w.id = d.webhook_id AND w.active
+	WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+	ORDER BY d.next_attempt_at ASC, d.id ASC
+	LIMIT $3
+	FOR UPDATE OF d SKIP LOCKED
+)
+UPDATE webhook_deliveries d
+SET next_attempt_at = $2
+FROM due, webhooks w
+WHERE d.id = due.id AND w.id = d.webhook_id
+RETURNING `+deliveryColumns+`, w.url, w.secret
+`, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]DueDelivery, 0)
+	for rows.Next() {
+		var item DueDelivery
+		item.Delivery, err = scanDelivery(rows, &item.URL, &item.Secret)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// RecordAttempt 写入一次尝试记录并更新投递状态。
+func (r *webhookRepository) RecordAttempt(ctx context.Context, input WebhookAttemptInput) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var statusCode *int
+	if input.StatusCode > 0 {
+		statusCode = &input.StatusCode
+	}
+	var deliveredAt *time.Time
+	if input.Status == webhook.DeliverySucceeded {
+		deliveredAt = &input.At
+	}
+
+	var attempt int
+	err = tx.QueryRowContext(ctx, `
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+	status = $2,
+	next_attempt_at = $3,
+	last_status_code = $4,
+	last_error = $5,
+	delivered_at = $6
+WHERE id = $1
+RETURNING attempts
+`, input.DeliveryID, string(input.Status), input.NextAttemptAt, statusCode, input.Error, deliveredAt).Scan(&attempt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, response_body, duration_ms, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`, input.DeliveryID, attempt, statusCode, input.Error, input.ResponseBody, input.Duration.Milliseconds(), input.At); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanWebhook(row rowScanner) (webhook.Webhook, error) {
+	var (
+		hook      webhook.Webhook
+		eventsRaw []byte
+		createdBy uuid.NullUUID
+	)
+	if err := row.Scan(
+		&hook.ID,
+		&hook.URL,
+		&hook.Secret,
+		&eventsRaw,
+		&hook.Description,
+		&hook.Active,
+		&createdBy,
+		&hook.CreatedAt,
+		&hook.UpdatedAt,
+	); err != nil {
+		return webhook.Webhook{}, err
+	}
+	hook.Events = make([]string, 0)
+	if len(eventsRaw) > 0 {
+		if err := json.Unmarshal(eventsRaw, &hook.Events); err != nil {
+			return webhook.Webhook{}, err
+		}
+	}
+	hook.CreatedBy = createdBy.UUID
+	return hook, nil
+}
+
+// scanDelivery 扫描 deliveryColumns，extra 用于接收查询末尾追加的列。
+func scanDelivery(row rowScanner, extra ...any) (webhook.Delivery, error) {
+	var (
+		item          webhook.Delivery
+		payload       []byte
+		nextAttemptAt sql.NullTime
+		statusCode    sql.NullInt64
+		redeliveryOf  sql.NullInt64
+		deliveredAt   sql.NullTime
+	)
+	dest := []any{
+		&item.ID,
+		&item.WebhookID,
+		&item.EventID,
+		&item.EventType,
+		&payload,
+		&item.Status,
+		&item.Attempts,
+		&nextAttemptAt,
+		&statusCode,
+		&item.LastError,
+		&redeliveryOf,
+		&item.CreatedAt,
+		&deliveredAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return webhook.Delivery{}, err
+	}
+	item.Payload = payload
+	if nextAttemptAt.Valid {
+		t := nextAttemptAt.Time
+		item.NextAttemptAt = &t
+	}
+	if statusCode.Valid {
+		code := int(statusCode.Int64)
+		item.LastStatusCode = &code
+	}
+	if redeliveryOf.Valid {
+		id := redeliveryOf.Int64
+		item.RedeliveryOf = &id
+	}
+	if deliveredAt.Valid {
+		t := deliveredAt.Time
+		item.DeliveredAt = &t
+	}
+	return item, nil
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+	"backend/internal/repository"
+	"backend/internal/storage"
+
+	"go.uber.org/zap"
+)
+
+// AttachmentService 管理任务与提交的附件，附件内容按 SHA256 去重保存在 BlobStore 中。
+type AttachmentService struct {
+	repo     repository.AttachmentRepository
+	tasks    *TaskService
+	blobs    storage.BlobStore
+	maxBytes int64
+	log      *zap.Logger
+}
+
+// AttachmentUploadInput 描述一次附件上传，SubmissionID 非空时附件归属该轮提交。
+type AttachmentUploadInput struct {
+	TaskID        uuid.UUID
+	SubmissionID  *int64
+	UploaderID    uuid.UUID
+	UploaderRoles []string
+	Filename      string
+	Content       io.Reader
+}
+
+// NewAttachmentService 构造附件服务。
+func NewAttachmentService(repo repository.AttachmentRepository, tasks *TaskService, blobs storage.BlobStore, maxBytes int64, log *zap.Logger) *AttachmentService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &AttachmentService{repo: repo, tasks: tasks, blobs: blobs, maxBytes: maxBytes, log: log}
+}
+
+// MaxUploadBytes 返回单个附件的大小上限。
+func (s *AttachmentService) MaxUploadBytes() int64 {
+	return s.maxBytes
+}
+
+// ListAttachments 返回任务的全部附件，可见范围与任务详情一致。
+func (s *AttachmentService) ListAttachments(ctx context.Context, taskID uuid.UUID) ([]task.Attachment, error) {
+	if _, err := s.tasks.GetTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTask(ctx, taskID)
+}
+
+// UploadAttachment 保存附件：内容类型由服务端嗅探，不信任客户端声明。
+// 任务发布人、管理员与当前执行人可上传到任务；提交附件仅限该轮提交人或管理员上传。
+func (s *AttachmentService) UploadAttachment(ctx context.Context, input AttachmentUploadInput) (task.Attachment, error) {
+	tk, err := s.tasks.GetTask(ctx, input.TaskID)
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	if !s.canUpload(tk, input) {
+		return task.Attachment{}, ErrForbidden
+	}
+
+	filename := sanitizeFilename(input.Filename)
+
+	// 先落盘到临时文件并计算摘要，确认大小与去重后再写入存储。
+	tmp, err := os.CreateTemp("", "opsboard-upload-*")
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(input.Content, s.maxBytes+1))
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	if size > s.maxBytes {
+		return task.Attachment{}, fmt.Errorf("%w: attachment exceeds %d bytes", ErrPayloadTooLarge, s.maxBytes)
+	}
+	if size == 0 {
+		return task.Attachment{}, fmt.Errorf("%w: attachment is empty", ErrValidation)
+	}
+
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return task.Attachment{}, err
+	}
+
+	att := task.Attachment{
+		TaskID:       input.TaskID,
+		SubmissionID: input.SubmissionID,
+		Filename:     filename,
+		ContentType:  sniffContentType(head[:n]),
+		Size:         size,
+		SHA256:       hex.EncodeToString(hasher.Sum(nil)),
+		UploadedBy:   input.UploaderID,
+	}
+
+	exists, err := s.blobs.Exists(ctx, att.BlobKey())
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	if !exists {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return task.Attachment{}, err
+		}
+		if err := s.blobs.Put(ctx, att.BlobKey(), tmp, size, att.ContentType); err != nil {
+			return task.Attachment{}, err
+		}
+	}
+
+	created, err := s.repo.Create(ctx, att)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Attachment{}, fmt.Errorf("%w: submission does not belong to task", ErrValidation)
+	}
+	return created, err
+}
+
+// OpenAttachment 返回附件元数据与内容，调用方负责关闭 reader；可见范围与任务详情一致。
+func (s *AttachmentService) OpenAttachment(ctx context.Context, taskID, id uuid.UUID) (task.Attachment, io.ReadCloser, error) {
+	if _, err := s.tasks.GetTask(ctx, taskID); err != nil {
+		return task.Attachment{}, nil, err
+	}
+	att, err := s.getAttachment(ctx, taskID, id)
+	if err != nil {
+		return task.Attachment{}, nil, err
+	}
+
+	body, err := s.blobs.Open(ctx, att.BlobKey())
+	if errors.Is(err, storage.ErrNotFound) {
+		s.log.Error("attachment blob missing", zap.String("attachment_id", id.String()), zap.String("sha256", att.SHA256))
+		return task.Attachment{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return task.Attachment{}, nil, err
+	}
+	return att, body, nil
+}
+
+// DeleteAttachment 删除附件，仅上传人或管理员可操作。
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, taskID, id, actorID uuid.UUID, actorRoles []string) error {
+	att, err := s.getAttachment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if att.UploadedBy != actorID && !hasAdminRole(actorRoles) {
+		return ErrForbidden
+	}
+
+	err = s.repo.Delete(ctx, id, actorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *AttachmentService) getAttachment(ctx context.Context, taskID, id uuid.UUID) (task.Attachment, error) {
+	att, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Attachment{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Attachment{}, err
+	}
+	if att.TaskID != taskID {
+		return task.Attachment{}, ErrNotFound
+	}
+	return att, nil
+}
+
+func (s *AttachmentService) canUpload(tk task.Task, input AttachmentUploadInput) bool {
+	if hasAdminRole(input.UploaderRoles) {
+		return true
+	}
+	if input.SubmissionID != nil {
+		for _, sub := range tk.Submissions {
+			if sub.ID == *input.SubmissionID {
+				return sub.SubmittedBy == input.UploaderID
+			}
+		}
+		return false
+	}
+	if isTaskOwner(tk, input.UploaderID) {
+		return true
+	}
+	return tk.CurrentAssignee != nil && tk.CurrentAssignee.UserID == input.UploaderID
+}
+
+// sniffContentType 按内容判断类型；可被浏览器当作页面执行的类型一律降级为纯文本。
+func sniffContentType(head []byte) string {
+	contentType := http.DetectContentType(head)
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	switch mediaType {
+	case "text/html", "text/xml", "image/svg+xml", "application/xml":
+		return "text/plain; charset=utf-8"
+	}
+	return contentType
+}
+
+// sanitizeFilename 去掉路径与控制字符，保留原始扩展名，超长时截断。
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if utf8.RuneCountInString(name) > 200 {
+		ext := path.Ext(name)
+		if utf8.RuneCountInString(ext) > 20 {
+			ext = ""
+		}
+		name = string([]rune(name)[:200-utf8.RuneCountInString(ext)]) + ext
+	}
+	return name
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`C:\Users\me\doc.txt`, "doc.txt"},
+		{"dir/sub/", "sub"},
+		{"a\x00b\r\nc.txt", "abc.txt"},
+		{`say "hi".txt`, "say hi.txt"},
+		{"  spaced.txt  ", "spaced.txt"},
+		{"", "attachment"},
+		{".", "attachment"},
+		{"/", "attachment"},
+		{"\x01\x02", "attachment"},
+		{"周报.docx", "周报.docx"},
+	}
+	for _, tc := range cases {
+		if got := sanitizeFilename(tc.in); got != tc.want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestSanitizeFilenameTruncates(t *testing.T) {
+	long := strings.Repeat("文", 300) + ".xlsx"
+	got := sanitizeFilename(long)
+	if n := utf8.RuneCountInString(got); n != 200 {
+		t.Fatalf("length = %d, want 200", n)
+	}
+	if !strings.HasSuffix(got, ".xlsx") {
+		t.Fatalf("extension lost: %q", got)
+	}
+
+	// 过长的扩展名不保留，直接截断。
+	longExt := "name." + strings.Repeat("x", 250)
+	got = sanitizeFilename(longExt)
+	if n := utf8.RuneCountInString(got); n != 200 {
+		t.Fatalf("length = %d, want 200", n)
+	}
+	if !strings.HasPrefix(got, "name.") {
+		t.Fatalf("unexpected prefix: %q", got)
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	cases := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"pdf", []byte("%PDF-1.7\n"), "application/pdf"},
+		{"plain", []byte("hello world"), "text/plain; charset=utf-8"},
+		{"html downgraded", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), "text/plain; charset=utf-8"},
+		{"xml downgraded", []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`), "text/plain; charset=utf-8"},
+		{"binary", []byte{0x00, 0x01, 0x02, 0x03}, "application/octet-stream"},
+	}
+	for _, tc := range cases {
+		if got := sniffContentType(tc.head); got != tc.want {
+			t.Errorf("%s: sniffContentType = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
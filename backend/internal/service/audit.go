@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/audit"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// AuditService 提供审计日志检索与导出。
+type AuditService struct {
+	repo repository.AuditRepository
+	log  *zap.Logger
+}
+
+// AuditQueryInput 描述审计日志筛选条件。
+type AuditQueryInput struct {
+	ActorID    uuid.UUID
+	Actions    []string
+	Resource   string
+	ResourceID string
+	From       *time.Time
+	To         *time.Time
+	Cursor     int64
+	Limit      int
+}
+
+// AuditListResult 返回一页审计日志，NextCursor 为 0 表示没有更多数据。
+type AuditListResult struct {
+	Items      []audit.Log
+	NextCursor int64
+}
+
+// NewAuditService 构造审计日志服务。
+func NewAuditService(repo repository.AuditRepository, log *zap.Logger) *AuditService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &AuditService{repo: repo, log: log}
+}
+
+// ListLogs 按 id 倒序分页返回审计日志。
+func (s *AuditService) ListLogs(ctx context.Context, input AuditQueryInput) (AuditListResult, error) {
+	filter, err := s.buildFilter(input)
+	if err != nil {
+		return AuditListResult{}, err
+	}
+	limit := input.Limit
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	// 多取一条用于判断是否存在下一页。
+	filter.Limit = limit + 1
+
+	items, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return AuditListResult{}, err
+	}
+
+	result := AuditListResult{Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		result.NextCursor = result.Items[limit-1].ID
+	}
+	return result, nil
+}
+
+// ExportLogs 将满足条件的全部审计日志逐条交给 fn 处理。
+func (s *AuditService) ExportLogs(ctx context.Context, input AuditQueryInput, fn func(audit.Log) error) error {
+	filter, err := s.buildFilter(input)
+	if err != nil {
+		return err
+	}
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+func (s *AuditService) buildFilter(input AuditQueryInput) (repository.AuditFilter, error) {
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return repository.AuditFilter{}, fmt.Errorf("%w: invalid time range", ErrValidation)
+	}
+	if input.Cursor < 0 {
+		return repository.AuditFilter{}, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	actions := make([]audit.Action, 0, len(input.Actions))
+	for _, action := range input.Actions {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			continue
+		}
+		actions = append(actions, audit.Action(action))
+	}
+
+	return repository.AuditFilter{
+		ActorID:    input.ActorID,
+		Actions:    actions,
+		Resource:   strings.TrimSpace(input.Resource),
+		ResourceID: strings.TrimSpace(input.ResourceID),
+		From:       input.From,
+		To:         input.To,
+		BeforeID:   input.Cursor,
+	}, nil
+}
@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"backend/internal/chatbot"
+	"backend/internal/config"
+	"backend/internal/domain/chat"
+	"backend/internal/domain/outbox"
+	"backend/internal/domain/task"
+	"backend/internal/events"
+	"backend/internal/repository"
+)
+
+const (
+	chatBatchSize         = 10
+	maxChatChannelNameLen = 40
+	maxChatSecretLen      = 200
+	maxChatTags           = 20
+)
+
+// chatEventTitles 为允许推送的事件类型及其卡片标题。
+var chatEventTitles = map[string]string{
+	events.TypeTaskPublished: "新任务发布",
+	events.TypeTaskCreated:   "任务已创建",
+	events.TypeTaskUpdated:   "任务已更新",
+	events.TypeTaskClaimed:   "任务已被领取",
+	events.TypeTaskSubmitted: "任务已提交验收",
+	events.TypeTaskRejected:  "任务未通过验收",
+	events.TypeTaskCompleted: "任务已完成",
+}
+
+var priorityLabels = map[task.Priority]string{
+	task.PriorityCritical: "紧急",
+	task.PriorityHigh:     "高",
+	task.PriorityMedium:   "中",
+	task.PriorityLow:      "低",
+}
+
+// ChatService 管理群机器人频道，并将任务事件格式化为卡片推送到企业微信、钉钉或飞书群。
+// 发件箱分发事件时只为每个频道写入待推送记录，由后台巡检发送并按指数退避重试，各频道互不影响。
+type ChatService struct {
+	cfg    config.ChatConfig
+	repo   repository.ChatChannelRepository
+	client *chatbot.Client
+	log    *zap.Logger
+}
+
+// ChatChannelInput 描述创建频道所需字段，Events 为空时默认只推送任务发布。
+type ChatChannelInput struct {
+	Name       string
+	Provider   string
+	WebhookURL string
+	Secret     string
+	Events     []string
+	Tags       []string
+	Priorities []string
+	Active     bool
+	CreatedBy  uuid.UUID
+}
+
+// ChatChannelUpdateInput 描述频道可更新字段，nil 表示保持不变。
+type ChatChannelUpdateInput struct {
+	ID         uuid.UUID
+	ActorID    uuid.UUID
+	Name       *string
+	Provider   *string
+	WebhookURL *string
+	Secret     *string
+	Events     *[]string
+	Tags       *[]string
+	Priorities *[]string
+	Active     *bool
+}
+
+// NewChatService 构造群机器人服务。
+func NewChatService(cfg config.ChatConfig, repo repository.ChatChannelRepository, log *zap.Logger) *ChatService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &ChatService{
+		cfg:    cfg,
+		repo:   repo,
+		client: chatbot.NewClient(cfg.Timeout),
+		log:    log,
+	}
+}
+
+// ListChannels 返回全部频道。
+func (s *ChatService) ListChannels(ctx context.Context) ([]chat.Channel, error) {
+	return s.repo.List(ctx)
+}
+
+// GetChannel 返回频道详情。
+func (s *ChatService) GetChannel(ctx context.Context, id uuid.UUID) (chat.Channel, error) {
+	channel, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.Channel{}, ErrNotFound
+	}
+	return channel, err
+}
+
+// CreateChannel 新建频道。
+func (s *ChatService) CreateChannel(ctx context.Context, input ChatChannelInput) (chat.Channel, error) {
+	channel := chat.Channel{
+		Name:       strings.TrimSpace(input.Name),
+		Provider:   chat.Provider(strings.TrimSpace(input.Provider)),
+		WebhookURL: strings.TrimSpace(input.WebhookURL),
+		Secret:     strings.TrimSpace(input.Secret),
+		Active:     input.Active,
+		CreatedBy:  input.CreatedBy,
+	}
+	var err error
+	if channel.Events, err = normalizeChatEvents(input.Events); err != nil {
+		return chat.Channel{}, err
+	}
+	if channel.Tags, err = normalizeChatTags(input.Tags); err != nil {
+		return chat.Channel{}, err
+	}
+	if channel.Priorities, err = normalizeChatPriorities(input.Priorities); err != nil {
+		return chat.Channel{}, err
+	}
+	if err := validateChatChannel(channel); err != nil {
+		return chat.Channel{}, err
+	}
+	return s.repo.Create(ctx, channel)
+}
+
+// UpdateChannel 更新频道字段。
+func (s *ChatService) UpdateChannel(ctx context.Context, input ChatChannelUpdateInput) (chat.Channel, error) {
+	channel, err := s.GetChannel(ctx, input.ID)
+	if err != nil {
+		return chat.Channel{}, err
+	}
+
+	if input.Name != nil {
+		channel.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Provider != nil {
+		channel.Provider = chat.Provider(strings.TrimSpace(*input.Provider))
+	}
+	if input.WebhookURL != nil {
+		channel.WebhookURL = strings.TrimSpace(*input.WebhookURL)
+	}
+	if input.Secret != nil {
+		channel.Secret = strings.TrimSpace(*input.Secret)
+	}
+	if input.Events != nil {
+		if channel.Events, err = normalizeChatEvents(*input.Events); err != nil {
+			return chat.Channel{}, err
+		}
+	}
+	if input.Tags != nil {
+		if channel.Tags, err = normalizeChatTags(*input.Tags); err != nil {
+			return chat.Channel{}, err
+		}
+	}
+	if input.Priorities != nil {
+		if channel.Priorities, err = normalizeChatPriorities(*input.Priorities); err != nil {
+			return chat.Channel{}, err
+		}
+	}
+	if input.Active != nil {
+		channel.Active = *input.Active
+	}
+	if err := validateChatChannel(channel); err != nil {
+		return chat.Channel{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, channel, input.ActorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.Channel{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// DeleteChannel 删除频道。
+func (s *ChatService) DeleteChannel(ctx context.Context, id, actor uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id, actor); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// TestChannel 向频道发送一条测试消息，用于核对机器人地址与加签密钥；平台返回的错误原样提示给管理员。
+func (s *ChatService) TestChannel(ctx context.Context, id uuid.UUID) error {
+	channel, err := s.GetChannel(ctx, id)
+	if err != nil {
+		return err
+	}
+	card := chatbot.Card{
+		Title:  "OpsBoard 机器人连通测试",
+		Fields: []chatbot.Field{{Label: "频道", Value: channel.Name}},
+	}
+	if err := s.client.Send(ctx, channel.Provider, channel.WebhookURL, channel.Secret, card); err != nil {
+		return fmt.Errorf("%w: chat test failed: %v", ErrValidation, err)
+	}
+	return nil
+}
+
+// chatTaskEvent 为发件箱任务事件中生成卡片所需的字段，卡片内容以事件发生时的快照为准。
+type chatTaskEvent struct {
+	TaskID   string        `json:"taskId"`
+	Title    string        `json:"title"`
+	Priority task.Priority `json:"priority"`
+	Bounty   int64         `json:"bounty"`
+	Deadline *time.Time    `json:"deadline"`
+	Tags     []string      `json:"tags"`
+	Assignee *string       `json:"assignee"`
+}
+
+// Announce 为订阅了该事件且满足标签、优先级过滤的频道生成待推送记录，作为发件箱的分发目标调用。
+// 推送记录以发件箱事件 ID 去重，发件箱重试时不会重复推送；实际发送由 DeliverDue 完成。
+func (s *ChatService) Announce(ctx context.Context, msg outbox.Message) error {
+	title, ok := chatEventTitles[msg.Topic]
+	if !ok {
+		return nil
+	}
+	var evt chatTaskEvent
+	if err := json.Unmarshal(msg.Payload, &evt); err != nil {
+		s.log.Warn("chat announce skipped: invalid payload", zap.Int64("event_id", msg.ID), zap.Error(err))
+		return nil
+	}
+
+	channels, err := s.repo.ListActive(ctx, msg.Topic)
+	if err != nil {
+		return err
+	}
+	targets := make([]uuid.UUID, 0, len(channels))
+	for _, channel := range channels {
+		if channel.Matches(evt.Priority, evt.Tags) {
+			targets = append(targets, channel.ID)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	card, err := json.Marshal(s.taskCard(title, msg.Topic, evt))
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.EnqueueDeliveries(ctx, targets, strconv.FormatInt(msg.ID, 10), msg.Topic, card, time.Now().UTC())
+	return err
+}
+
+// DeliverDue 发送到期的推送并记录结果，返回本轮处理的数量。
+func (s *ChatService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	// 租约需覆盖一次请求的超时时间，避免其他副本在请求进行中重复领取。
+	due, err := s.repo.ClaimDueDeliveries(ctx, now, now.Add(s.cfg.Timeout+time.Minute), chatBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, item)
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// deliver 发送一条推送并记录结果，失败时按退避间隔重试，次数用尽后标记为失败。
+func (s *ChatService) deliver(ctx context.Context, item repository.DueChatDelivery) {
+	var (
+		card    chatbot.Card
+		sendErr error
+	)
+	if sendErr = json.Unmarshal(item.Delivery.Card, &card); sendErr == nil {
+		sendErr = s.client.Send(ctx, item.Provider, item.WebhookURL, item.Secret, card)
+	}
+	if ctx.Err() != nil {
+		// 进程正在退出，租约到期后会重新推送，本次不计入尝试次数。
+		return
+	}
+
+	result := repository.ChatAttemptInput{
+		DeliveryID: item.Delivery.ID,
+		Status:     chat.DeliverySucceeded,
+		At:         time.Now().UTC(),
+	}
+	if sendErr != nil {
+		result.Error = sendErr.Error()
+		attempt := item.Delivery.Attempts + 1
+		if attempt >= s.cfg.MaxAttempts {
+			result.Status = chat.DeliveryFailed
+		} else {
+			result.Status = chat.DeliveryPending
+			next := result.At.Add(s.retryDelay(attempt))
+			result.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.repo.RecordAttempt(ctx, result); err != nil {
+		s.log.Error("record chat delivery failed", zap.Int64("delivery_id", item.Delivery.ID), zap.Error(err))
+		return
+	}
+	if result.Status == chat.DeliveryFailed {
+		s.log.Warn("chat delivery failed permanently",
+			zap.Int64("delivery_id", item.Delivery.ID),
+			zap.String("channel_id", item.Delivery.ChannelID.String()),
+			zap.String("provider", string(item.Provider)),
+			zap.String("error", result.Error),
+		)
+	}
+}
+
+// retryDelay 返回第 attempt 次失败后的等待时间：RetryBase * 2^(attempt-1)，不超过 RetryMax。
+func (s *ChatService) retryDelay(attempt int) time.Duration {
+	delay := s.cfg.RetryBase
+	for i := 1; i < attempt && delay < s.cfg.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.RetryMax)
+}
+
+func (s *ChatService) taskCard(title, eventType string, evt chatTaskEvent) chatbot.Card {
+	card := chatbot.Card{
+		Title:     title + "：" + evt.Title,
+		Highlight: evt.Priority == task.PriorityCritical,
+	}
+	priority := priorityLabels[evt.Priority]
+	if priority == "" {
+		priority = string(evt.Priority)
+	}
+	card.Fields = append(card.Fields,
+		chatbot.Field{Label: "优先级", Value: priority},
+		chatbot.Field{Label: "赏金", Value: strconv.FormatInt(evt.Bounty, 10) + " 积分"},
+	)
+	if evt.Deadline != nil {
+		card.Fields = append(card.Fields, chatbot.Field{Label: "截止时间", Value: evt.Deadline.In(time.Local).Format("2006-01-02 15:04")})
+	}
+	if len(evt.Tags) > 0 {
+		card.Fields = append(card.Fields, chatbot.Field{Label: "标签", Value: strings.Join(evt.Tags, "、")})
+	}
+	if eventType == events.TypeTaskClaimed && evt.Assignee != nil {
+		card.Fields = append(card.Fields, chatbot.Field{Label: "领取人", Value: *evt.Assignee})
+	}
+	if s.cfg.LinkBaseURL != "" {
+		card.Link = s.cfg.LinkBaseURL + "/home?task=" + evt.TaskID
+	}
+	return card
+}
+
+func normalizeChatEvents(input []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(input))
+	out := make([]string, 0, len(input))
+	for _, raw := range input {
+		evt := strings.TrimSpace(raw)
+		if evt == "" {
+			continue
+		}
+		if _, ok := chatEventTitles[evt]; !ok {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrValidation, evt)
+		}
+		if _, ok := seen[evt]; ok {
+			continue
+		}
+		seen[evt] = struct{}{}
+		out = append(out, evt)
+	}
+	if len(out) == 0 {
+		out = append(out, events.TypeTaskPublished)
+	}
+	return out, nil
+}
+
+func normalizeChatTags(input []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(input))
+	out := make([]string, 0, len(input))
+	for _, raw := range input {
+		tag := strings.TrimSpace(raw)
+		if tag == "" {
+			continue
+		}
+		key := strings.ToLower(tag)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, tag)
+	}
+	if len(out) > maxChatTags {
+		return nil, fmt.Errorf("%w: too many tags", ErrValidation)
+	}
+	return out, nil
+}
+
+func normalizeChatPriorities(input []string) ([]task.Priority, error) {
+	seen := make(map[task.Priority]struct{}, len(input))
+	out := make([]task.Priority, 0, len(input))
+	for _, raw := range input {
+		p := task.Priority(strings.ToLower(strings.TrimSpace(raw)))
+		if p == "" {
+			continue
+		}
+		if _, ok := priorityLabels[p]; !ok {
+			return nil, fmt.Errorf("%w: unknown priority %q", ErrValidation, p)
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func validateChatChannel(channel chat.Channel) error {
+	if channel.Name == "" {
+		return fmt.Errorf("%w: name required", ErrValidation)
+	}
+	if utf8.RuneCountInString(channel.Name) > maxChatChannelNameLen {
+		return fmt.Errorf("%w: name too long", ErrValidation)
+	}
+	switch channel.Provider {
+	case chat.ProviderWeCom, chat.ProviderDingTalk, chat.ProviderFeishu:
+	default:
+		return fmt.Errorf("%w: provider must be wecom, dingtalk or feishu", ErrValidation)
+	}
+	if channel.WebhookURL == "" {
+		return fmt.Errorf("%w: webhook url required", ErrValidation)
+	}
+	if len(channel.WebhookURL) > maxWebhookURLLen {
+		return fmt.Errorf("%w: webhook url too long", ErrValidation)
+	}
+	parsed, err := url.Parse(channel.WebhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: webhook url must be an absolute http(s) address", ErrValidation)
+	}
+	if channel.Provider == chat.ProviderWeCom && channel.Secret != "" {
+		return fmt.Errorf("%w: wecom robots do not support signing secrets", ErrValidation)
+	}
+	if len(channel.Secret) > maxChatSecretLen {
+		return fmt.Errorf("%w: secret too long", ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/chatbot"
+	"backend/internal/config"
+	"backend/internal/domain/chat"
+	"backend/internal/domain/outbox"
+	"backend/internal/domain/task"
+	"backend/internal/events"
+	"backend/internal/repository"
+)
+
+type enqueuedChat struct {
+	channelIDs []uuid.UUID
+	eventID    string
+	eventType  string
+	card       []byte
+}
+
+// fakeChatRepo 只实现推送用到的方法。
+type fakeChatRepo struct {
+	repository.ChatChannelRepository
+
+	channels []chat.Channel
+	enqueued []enqueuedChat
+	due      []repository.DueChatDelivery
+	attempts []repository.ChatAttemptInput
+}
+
+func (f *fakeChatRepo) ListActive(_ context.Context, eventType string) ([]chat.Channel, error) {
+	out := make([]chat.Channel, 0)
+	for _, channel := range f.channels {
+		if channel.Active && channel.Subscribes(eventType) {
+			out = append(out, channel)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeChatRepo) EnqueueDeliveries(_ context.Context, channelIDs []uuid.UUID, eventID, eventType string, card []byte, _ time.Time) (int, error) {
+	f.enqueued = append(f.enqueued, enqueuedChat{channelIDs: channelIDs, eventID: eventID, eventType: eventType, card: card})
+	return len(channelIDs), nil
+}
+
+func (f *fakeChatRepo) ClaimDueDeliveries(_ context.Context, _, _ time.Time, _ int) ([]repository.DueChatDelivery, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeChatRepo) RecordAttempt(_ context.Context, input repository.ChatAttemptInput) error {
+	f.attempts = append(f.attempts, input)
+	return nil
+}
+
+func TestChatAnnounceQueuesCardFromPayload(t *testing.T) {
+	matching := chat.Channel{ID: uuid.New(), Active: true, Events: []string{events.TypeTaskPublished}, Tags: []string{"backend"}}
+	wrongTag := chat.Channel{ID: uuid.New(), Active: true, Events: []string{events.TypeTaskPublished}, Tags: []string{"frontend"}}
+	wrongPriority := chat.Channel{ID: uuid.New(), Active: true, Events: []string{events.TypeTaskPublished}, Priorities: []task.Priority{task.PriorityLow}}
+	otherEvent := chat.Channel{ID: uuid.New(), Active: true, Events: []string{events.TypeTaskCompleted}}
+	repo := &fakeChatRepo{channels: []chat.Channel{matching, wrongTag, wrongPriority, otherEvent}}
+	svc := NewChatService(config.ChatConfig{LinkBaseURL: "https://ops.example.com"}, repo, nil)
+
+	taskID := uuid.New()
+	payload, _ := json.Marshal(map[string]any{
+		"taskId":   taskID.String(),
+		"title":    "修复登录页",
+		"status":   "available",
+		"priority": "critical",
+		"bounty":   120,
+		"deadline": "2024-03-05T10:00:00Z",
+		"tags":     []string{"Backend", "auth"},
+		"action":   "task_publish",
+	})
+	msg := outbox.Message{ID: 99, Topic: events.TypeTaskPublished, AggregateID: taskID.String(), Payload: payload}
+
+	if err := svc.Announce(context.Background(), msg); err != nil {
+		t.Fatalf("Announce: %v", err)
+	}
+	if len(repo.enqueued) != 1 {
+		t.Fatalf("enqueued %d batches, want 1", len(repo.enqueued))
+	}
+	got := repo.enqueued[0]
+	if len(got.channelIDs) != 1 || got.channelIDs[0] != matching.ID {
+		t.Fatalf("channels = %v, want only %s", got.channelIDs, matching.ID)
+	}
+	if got.eventID != "99" || got.eventType != events.TypeTaskPublished {
+		t.Fatalf("event = %s %s", got.eventID, got.eventType)
+	}
+
+	var card chatbot.Card
+	if err := json.Unmarshal(got.card, &card); err != nil {
+		t.Fatalf("card: %v", err)
+	}
+	if card.Title != "新任务发布：修复登录页" || !card.Highlight {
+		t.Fatalf("card = %+v", card)
+	}
+	if card.Link != "https://ops.example.com/home?task="+taskID.String() {
+		t.Fatalf("link = %q", card.Link)
+	}
+	fields := make(map[string]string, len(card.Fields))
+	for _, f := range card.Fields {
+		fields[f.Label] = f.Value
+	}
+	if fields["优先级"] != "紧急" || fields["赏金"] != "120 积分" || fields["标签"] != "Backend、auth" || fields["截止时间"] == "" {
+		t.Fatalf("fields = %v", fields)
+	}
+}
+
+func TestChatDeliverRetriesThenFails(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":310000,"errmsg":"keywords not in content"}`))
+	}))
+	defer srv.Close()
+
+	card, _ := json.Marshal(chatbot.Card{Title: "任务已完成：x"})
+	due := func(attempts int) repository.DueChatDelivery {
+		return repository.DueChatDelivery{
+			Delivery:   chat.Delivery{ID: 5, ChannelID: uuid.New(), Card: card, Status: chat.DeliveryPending, Attempts: attempts},
+			Provider:   chat.ProviderDingTalk,
+			WebhookURL: srv.URL,
+		}
+	}
+	cfg := config.ChatConfig{Timeout: time.Second, MaxAttempts: 3, RetryBase: 30 * time.Second, RetryMax: time.Hour}
+
+	repo := &fakeChatRepo{due: []repository.DueChatDelivery{due(1)}}
+	svc := NewChatService(cfg, repo, nil)
+	if _, err := svc.DeliverDue(context.Background(), time.Now()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	got := repo.attempts[0]
+	if got.Status != chat.DeliveryPending || got.Error == "" || got.NextAttemptAt == nil {
+		t.Fatalf("attempt = %+v, want pending retry", got)
+	}
+	if delay := got.NextAttemptAt.Sub(got.At); delay != time.Minute {
+		t.Fatalf("retry delay = %s, want 1m", delay)
+	}
+
+	repo.due = []repository.DueChatDelivery{due(2)}
+	if _, err := svc.DeliverDue(context.Background(), time.Now()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if got := repo.attempts[1]; got.Status != chat.DeliveryFailed || got.NextAttemptAt != nil {
+		t.Fatalf("attempt = %+v, want failed", got)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+	"backend/internal/repository"
+)
+
+const (
+	maxChecklistItems   = 50
+	maxChecklistItemLen = 200
+)
+
+// ChecklistItemInput 描述检查清单条目的可选修改字段。
+type ChecklistItemInput struct {
+	TaskID     uuid.UUID
+	ItemID     int64
+	Text       *string
+	Position   *int
+	ActorID    uuid.UUID
+	ActorRoles []string
+}
+
+// AddChecklistItem 为任务追加检查清单条目，仅任务创建者或管理员可编辑清单。
+func (s *TaskService) AddChecklistItem(ctx context.Context, taskID uuid.UUID, text string, actorID uuid.UUID, actorRoles []string) (task.Task, error) {
+	tk, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if !canModerateTask(tk, actorID, actorRoles) {
+		return task.Task{}, ErrForbidden
+	}
+	if tk.ChecklistTotal >= maxChecklistItems {
+		return task.Task{}, fmt.Errorf("%w: at most %d checklist items", ErrValidation, maxChecklistItems)
+	}
+	cleaned, err := normalizeChecklistText(text)
+	if err != nil {
+		return task.Task{}, err
+	}
+	return mapChecklistError(s.repo.AddChecklistItem(ctx, taskID, cleaned, actorID))
+}
+
+// UpdateChecklistItem 修改条目文本或顺序。
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, input ChecklistItemInput) (task.Task, error) {
+	tk, err := s.GetTask(ctx, input.TaskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if !canModerateTask(tk, input.ActorID, input.ActorRoles) {
+		return task.Task{}, ErrForbidden
+	}
+
+	update := repository.ChecklistItemUpdateInput{TaskID: input.TaskID, ItemID: input.ItemID, Position: input.Position, ActorID: input.ActorID}
+	if input.Text != nil {
+		cleaned, err := normalizeChecklistText(*input.Text)
+		if err != nil {
+			return task.Task{}, err
+		}
+		update.Text = &cleaned
+	}
+	return mapChecklistError(s.repo.UpdateChecklistItem(ctx, update))
+}
+
+// DeleteChecklistItem 删除检查清单条目。
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID int64, actorID uuid.UUID, actorRoles []string) (task.Task, error) {
+	tk, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	if !canModerateTask(tk, actorID, actorRoles) {
+		return task.Task{}, ErrForbidden
+	}
+	return mapChecklistError(s.repo.DeleteChecklistItem(ctx, taskID, itemID, actorID))
+}
+
+// SetChecklistItemDone 勾选或取消勾选条目，仅当前执行人或管理员可操作。
+func (s *TaskService) SetChecklistItemDone(ctx context.Context, taskID uuid.UUID, itemID int64, done bool, actorID uuid.UUID, actorRoles []string) (task.Task, error) {
+	tk, err := s.GetTask(ctx, taskID)
+	if err != nil {
+		return task.Task{}, err
+	}
+	isAssignee := tk.CurrentAssignee != nil && tk.CurrentAssignee.UserID == actorID
+	if !isAssignee && !hasAdminRole(actorRoles) {
+		return task.Task{}, ErrForbidden
+	}
+	return mapChecklistError(s.repo.SetChecklistItemDone(ctx, taskID, itemID, done, actorID))
+}
+
+func normalizeChecklist(items []string) ([]string, error) {
+	if len(items) > maxChecklistItems {
+		return nil, fmt.Errorf("%w: at most %d checklist items", ErrValidation, maxChecklistItems)
+	}
+	cleaned := make([]string, 0, len(items))
+	for _, item := range items {
+		text, err := normalizeChecklistText(item)
+		if err != nil {
+			return nil, err
+		}
+		cleaned = append(cleaned, text)
+	}
+	return cleaned, nil
+}
+
+func normalizeChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: checklist item text required", ErrValidation)
+	}
+	if utf8.RuneCountInString(text) > maxChecklistItemLen {
+		return "", fmt.Errorf("%w: checklist item too long", ErrValidation)
+	}
+	return text, nil
+}
+
+func mapChecklistError(tk task.Task, err error) (task.Task, error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Task{}, ErrNotFound
+	}
+	return tk, err
+}
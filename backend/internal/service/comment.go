@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/microcosm-cc/bluemonday"
+
+	"backend/internal/domain/task"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxCommentHTMLBytes  = 20000
+	maxCommentPlainRunes = 5000
+	maxCommentMentions   = 20
+)
+
+// commentPolicy 是评论 HTML 的白名单，仅保留基础排版与 http(s)/mailto 链接。
+var commentPolicy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements("p", "br", "strong", "b", "em", "i", "u", "s", "code", "pre", "blockquote", "ul", "ol", "li")
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}()
+
+var (
+	plainTextPolicy = bluemonday.StrictPolicy()
+	mentionPattern  = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@-])@([\p{L}\p{N}_.-]+)`)
+)
+
+// CommentService 管理任务评论。
+type CommentService struct {
+	repo          repository.CommentRepository
+	notifications *NotificationService
+	log           *zap.Logger
+}
+
+// CommentInput 描述发表评论所需字段。
+type CommentInput struct {
+	TaskID   uuid.UUID
+	AuthorID uuid.UUID
+	BodyHTML string
+}
+
+// CommentUpdateInput 描述编辑评论所需字段。
+type CommentUpdateInput struct {
+	TaskID   uuid.UUID
+	ID       int64
+	ActorID  uuid.UUID
+	BodyHTML string
+}
+
+// NewCommentService 构造任务评论服务。
+func NewCommentService(repo repository.CommentRepository, notifications *NotificationService, log *zap.Logger) *CommentService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &CommentService{repo: repo, notifications: notifications, log: log}
+}
+
+// ListComments 按时间顺序返回任务下未删除的评论。
+func (s *CommentService) ListComments(ctx context.Context, taskID uuid.UUID) ([]task.Comment, error) {
+	comments, err := s.repo.List(ctx, taskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return comments, err
+}
+
+// CreateComment 发表评论，正文经白名单清洗后保存并解析 @ 提及。
+func (s *CommentService) CreateComment(ctx context.Context, input CommentInput) (task.Comment, error) {
+	bodyHTML, plain, mentions, err := prepareCommentBody(input.BodyHTML)
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	created, err := s.repo.Create(ctx, task.Comment{
+		TaskID:    input.TaskID,
+		AuthorID:  input.AuthorID,
+		BodyHTML:  bodyHTML,
+		BodyPlain: plain,
+	}, mentions)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Comment{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	s.notifications.CommentMentioned(ctx, created)
+	return created, nil
+}
+
+// UpdateComment 编辑评论，仅作者本人可编辑。
+func (s *CommentService) UpdateComment(ctx context.Context, input CommentUpdateInput) (task.Comment, error) {
+	current, err := s.getComment(ctx, input.TaskID, input.ID)
+	if err != nil {
+		return task.Comment{}, err
+	}
+	if current.AuthorID != input.ActorID {
+		return task.Comment{}, ErrForbidden
+	}
+
+	bodyHTML, plain, mentions, err := prepareCommentBody(input.BodyHTML)
+	if err != nil {
+		return task.Comment{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, input.ID, bodyHTML, plain, mentions, input.ActorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Comment{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// DeleteComment 软删除评论，作者本人或管理员可操作。
+func (s *CommentService) DeleteComment(ctx context.Context, taskID uuid.UUID, id int64, actorID uuid.UUID, actorRoles []string) error {
+	current, err := s.getComment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if current.AuthorID != actorID && !hasAdminRole(actorRoles) {
+		return ErrForbidden
+	}
+
+	err = s.repo.Delete(ctx, id, actorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *CommentService) getComment(ctx context.Context, taskID uuid.UUID, id int64) (task.Comment, error) {
+	comment, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Comment{}, ErrNotFound
+	}
+	if err != nil {
+		return task.Comment{}, err
+	}
+	if comment.TaskID != taskID {
+		return task.Comment{}, ErrNotFound
+	}
+	return comment, nil
+}
+
+// prepareCommentBody 清洗评论 HTML，返回清洗后的 HTML、纯文本与提及的用户名。
+func prepareCommentBody(raw string) (string, string, []string, error) {
+	if len(raw) > maxCommentHTMLBytes {
+		return "", "", nil, fmt.Errorf("%w: comment too long", ErrValidation)
+	}
+	bodyHTML := strings.TrimSpace(commentPolicy.Sanitize(raw))
+	plain := html.UnescapeString(plainTextPolicy.Sanitize(strings.NewReplacer("<br", " <br", "</p>", "</p> ", "</li>", "</li> ").Replace(bodyHTML)))
+	plain = strings.Join(strings.Fields(plain), " ")
+	if plain == "" {
+		return "", "", nil, fmt.Errorf("%w: comment body required", ErrValidation)
+	}
+	if utf8.RuneCountInString(plain) > maxCommentPlainRunes {
+		return "", "", nil, fmt.Errorf("%w: comment too long", ErrValidation)
+	}
+	return bodyHTML, plain, extractMentions(plain), nil
+}
+
+// extractMentions 提取纯文本中的 @username，按首次出现顺序去重。
+func extractMentions(plain string) []string {
+	seen := make(map[string]struct{})
+	names := make([]string, 0)
+	for _, match := range mentionPattern.FindAllStringSubmatch(plain, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		names = append(names, name)
+		if len(names) == maxCommentMentions {
+			break
+		}
+	}
+	return names
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/domain/notification"
+	"backend/internal/mail"
+	"backend/internal/repository"
+)
+
+const (
+	emailBatchSize = 20
+	// instantEmailWindow 之前产生的通知不再即时发送，避免开启邮件后补发大量旧通知。
+	instantEmailWindow = 24 * time.Hour
+	// digestMaxItems 为单封汇总邮件列出的最大条数，其余只给出数量。
+	digestMaxItems = 50
+	// digestRetryDelay 为汇总发送失败后再次尝试的等待时间。
+	digestRetryDelay = 15 * time.Minute
+	// testEmailCooldown 为同一用户两次发送测试邮件的最小间隔。
+	testEmailCooldown = time.Minute
+)
+
+// EmailService 将站内通知以邮件形式发送：即时模式逐条发送，每日汇总模式在设定时刻合并为一封。
+// 已在站内读过的通知不再发送邮件，发送失败时由下一轮巡检重试。
+type EmailService struct {
+	cfg           config.MailConfig
+	notifications repository.NotificationRepository
+	users         repository.UserRepository
+	sender        mail.Sender
+	log           *zap.Logger
+
+	testMu     sync.Mutex
+	testSentAt map[uuid.UUID]time.Time
+}
+
+// NewEmailService 构造邮件通知服务。
+func NewEmailService(cfg config.MailConfig, notifications repository.NotificationRepository, users repository.UserRepository, sender mail.Sender, log *zap.Logger) *EmailService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &EmailService{
+		cfg:           cfg,
+		notifications: notifications,
+		users:         users,
+		sender:        sender,
+		log:           log,
+		testSentAt:    make(map[uuid.UUID]time.Time),
+	}
+}
+
+// SendInstant 发送一批即时邮件，返回成功发送的数量。
+func (s *EmailService) SendInstant(ctx context.Context, now time.Time) (int, error) {
+	// 租约需覆盖整批逐封发送的最长耗时，避免其他副本在发送进行中重复领取。
+	lease := time.Duration(emailBatchSize)*s.cfg.Timeout + time.Minute
+	return s.notifications.DispatchInstantEmails(ctx, now.Add(-instantEmailWindow), emailBatchSize, lease, func(ctx context.Context, rcpt repository.EmailRecipient, item notification.Notification) error {
+		subject, text, err := mail.RenderNotification(mail.NotificationEmail{
+			RecipientName: rcpt.DisplayName,
+			Title:         item.Title,
+			Body:          item.Body,
+			TaskTitle:     item.TaskTitle,
+			ActorName:     item.ActorName,
+			Link:          s.taskLink(item.TaskID),
+			CreatedAt:     item.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.sender.Send(ctx, mail.Message{To: rcpt.Email, ToName: rcpt.DisplayName, Subject: subject, Text: text}); err != nil {
+			if ctx.Err() == nil {
+				s.log.Warn("send notification email failed", zap.Int64("notification_id", item.ID), zap.String("user_id", rcpt.UserID.String()), zap.Error(err))
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// SendDigests 为到达汇总时刻的每日汇总用户逐个发送汇总邮件，返回成功发送的封数。
+// 每位用户每期只会被领取一次；没有新通知的用户不发邮件，发送失败的用户恢复汇总时间，
+// 在 digestRetryDelay 之后重试，本轮继续处理其他用户。
+func (s *EmailService) SendDigests(ctx context.Context, now time.Time) (int, error) {
+	periodStart := digestPeriodStart(now, s.cfg.DigestHour)
+	sent := 0
+	for ctx.Err() == nil {
+		rcpt, ok, err := s.notifications.ClaimDigestRecipient(ctx, periodStart, now)
+		if err != nil {
+			return sent, err
+		}
+		if !ok {
+			return sent, nil
+		}
+		delivered, err := s.sendDigest(ctx, rcpt, periodStart, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			s.log.Warn("send digest email failed", zap.String("user_id", rcpt.UserID.String()), zap.Error(err))
+			if err := s.notifications.ResetDigest(ctx, rcpt, now.Add(digestRetryDelay)); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+	return sent, ctx.Err()
+}
+
+func (s *EmailService) sendDigest(ctx context.Context, rcpt repository.DigestRecipient, periodStart, now time.Time) (bool, error) {
+	since := periodStart.Add(-24 * time.Hour)
+	if rcpt.PreviousDigestAt != nil && rcpt.PreviousDigestAt.After(since) {
+		since = *rcpt.PreviousDigestAt
+	}
+
+	items, total, err := s.notifications.ListDigestItems(ctx, rcpt.UserID, since, digestMaxItems)
+	if err != nil {
+		return false, err
+	}
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	data := mail.DigestEmail{
+		RecipientName: rcpt.DisplayName,
+		Date:          now.In(time.Local).Format("2006-01-02"),
+		Items:         make([]mail.NotificationEmail, 0, len(items)),
+		Omitted:       total - len(items),
+	}
+	for _, item := range items {
+		data.Items = append(data.Items, mail.NotificationEmail{
+			Title:     item.Title,
+			Body:      item.Body,
+			TaskTitle: item.TaskTitle,
+			ActorName: item.ActorName,
+			Link:      s.taskLink(item.TaskID),
+			CreatedAt: item.CreatedAt.In(time.Local),
+		})
+	}
+	subject, text, err := mail.RenderDigest(data)
+	if err != nil {
+		return false, err
+	}
+	if err := s.sender.Send(ctx, mail.Message{To: rcpt.Email, ToName: rcpt.DisplayName, Subject: subject, Text: text}); err != nil {
+		return false, err
+	}
+	// 邮件已发出，标记失败时只记录日志，避免回退汇总时间导致重复发送。
+	if err := s.notifications.MarkEmailed(ctx, rcpt.UserID, since, now); err != nil {
+		s.log.Warn("mark digest items emailed failed", zap.String("user_id", rcpt.UserID.String()), zap.Error(err))
+	}
+	return true, nil
+}
+
+// SendTest 向当前用户的通知邮箱发送一封测试邮件，用于核对 SMTP 配置。
+// 同一用户在 testEmailCooldown 内只能发送一次，发送失败同样计入，避免被用来频繁调用 SMTP 服务。
+func (s *EmailService) SendTest(ctx context.Context, userID uuid.UUID) error {
+	if !s.cfg.Enabled {
+		return fmt.Errorf("%w: mail disabled", ErrValidation)
+	}
+	if !s.allowTest(userID, time.Now()) {
+		return fmt.Errorf("%w: test email sent recently", ErrRateLimited)
+	}
+	usr, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if usr.Email == "" {
+		return fmt.Errorf("%w: email not set", ErrValidation)
+	}
+
+	subject, text, err := mail.RenderNotification(mail.NotificationEmail{
+		RecipientName: usr.DisplayName,
+		Title:         "测试邮件",
+		Body:          "收到这封邮件说明 OpsBoard 的邮件通知已配置成功。",
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(ctx, mail.Message{To: usr.Email, ToName: usr.DisplayName, Subject: subject, Text: text})
+}
+
+// allowTest 判断用户是否已过测试邮件的冷却期，允许时记录本次时间并顺带清理过期记录。
+func (s *EmailService) allowTest(userID uuid.UUID, now time.Time) bool {
+	s.testMu.Lock()
+	defer s.testMu.Unlock()
+	if last, ok := s.testSentAt[userID]; ok && now.Sub(last) < testEmailCooldown {
+		return false
+	}
+	for id, last := range s.testSentAt {
+		if now.Sub(last) >= testEmailCooldown {
+			delete(s.testSentAt, id)
+		}
+	}
+	s.testSentAt[userID] = now
+	return true
+}
+
+func (s *EmailService) taskLink(taskID *uuid.UUID) string {
+	if taskID == nil || s.cfg.LinkBaseURL == "" {
+		return ""
+	}
+	return s.cfg.LinkBaseURL + "/home?task=" + taskID.String()
+}
+
+// digestPeriodStart 返回 now 所在汇总周期的起点，即最近一次到达的本地 hour 点。
+func digestPeriodStart(now time.Time, hour int) time.Time {
+	local := now.In(time.Local)
+	start := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, time.Local)
+	if local.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/config"
+	"backend/internal/domain/notification"
+	"backend/internal/domain/user"
+	"backend/internal/mail"
+	"backend/internal/repository"
+)
+
+type fakeSender struct {
+	sent []mail.Message
+	fail map[string]error
+}
+
+func (f *fakeSender) Send(_ context.Context, msg mail.Message) error {
+	if err := f.fail[msg.To]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+type markedRange struct {
+	userID       uuid.UUID
+	since, until time.Time
+}
+
+// fakeDigestRepo 按队列返回待汇总用户，每位用户的通知保存在 items 中；只实现汇总用到的方法。
+// 与数据库一致，发送失败被恢复的用户回到队首，到达重试时间后可再次领取。
+type fakeDigestRepo struct {
+	repository.NotificationRepository
+
+	queue   []repository.DigestRecipient
+	retryAt map[uuid.UUID]time.Time
+	items   map[uuid.UUID][]notification.Notification
+	marked  []markedRange
+	reset   []uuid.UUID
+	claims  int
+}
+
+func (f *fakeDigestRepo) ClaimDigestRecipient(_ context.Context, _, now time.Time) (repository.DigestRecipient, bool, error) {
+	f.claims++
+	for i, rcpt := range f.queue {
+		if at, ok := f.retryAt[rcpt.UserID]; ok && at.After(now) {
+			continue
+		}
+		f.queue = append(f.queue[:i:i], f.queue[i+1:]...)
+		return rcpt, true, nil
+	}
+	return repository.DigestRecipient{}, false, nil
+}
+
+func (f *fakeDigestRepo) ListDigestItems(_ context.Context, userID uuid.UUID, since time.Time, limit int) ([]notification.Notification, int, error) {
+	matched := make([]notification.Notification, 0)
+	for _, item := range f.items[userID] {
+		if !item.CreatedAt.Before(since) {
+			matched = append(matched, item)
+		}
+	}
+	total := len(matched)
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, total, nil
+}
+
+func (f *fakeDigestRepo) MarkEmailed(_ context.Context, userID uuid.UUID, since, until time.Time) error {
+	f.marked = append(f.marked, markedRange{userID: userID, since: since, until: until})
+	return nil
+}
+
+func (f *fakeDigestRepo) ResetDigest(_ context.Context, rcpt repository.DigestRecipient, retryAt time.Time) error {
+	f.reset = append(f.reset, rcpt.UserID)
+	if f.retryAt == nil {
+		f.retryAt = make(map[uuid.UUID]time.Time)
+	}
+	f.retryAt[rcpt.UserID] = retryAt
+	f.queue = append([]repository.DigestRecipient{rcpt}, f.queue...)
+	return nil
+}
+
+func digestRecipient(name string) repository.DigestRecipient {
+	return repository.DigestRecipient{EmailRecipient: repository.EmailRecipient{
+		UserID:      uuid.New(),
+		Email:       name + "@example.com",
+		DisplayName: name,
+	}}
+}
+
+func TestSendDigestsGroupsItemsPerRecipient(t *testing.T) {
+	now := time.Date(2024, 3, 5, 9, 5, 0, 0, time.Local)
+	periodStart := digestPeriodStart(now, 9)
+	alice := digestRecipient("alice")
+	bob := digestRecipient("bob")
+	idle := digestRecipient("idle")
+	broken := digestRecipient("broken")
+
+	repo := &fakeDigestRepo{
+		queue: []repository.DigestRecipient{alice, bob, idle, broken},
+		items: map[uuid.UUID][]notification.Notification{
+			alice.UserID: {
+				{ID: 1, UserID: alice.UserID, Title: "任务 A 已被领取", CreatedAt: now.Add(-3 * time.Hour)},
+				{ID: 2, UserID: alice.UserID, Title: "任务 B 已提交验收", CreatedAt: now.Add(-2 * time.Hour)},
+				// 上一期之前的通知不应出现在本期汇总中。
+				{ID: 3, UserID: alice.UserID, Title: "上上期的通知", CreatedAt: periodStart.Add(-48 * time.Hour)},
+			},
+			bob.UserID: {
+				{ID: 4, UserID: bob.UserID, Title: "任务 C 即将截止", CreatedAt: now.Add(-time.Hour)},
+			},
+			broken.UserID: {
+				{ID: 5, UserID: broken.UserID, Title: "任务 D 已完成", CreatedAt: now.Add(-time.Hour)},
+			},
+		},
+	}
+	sender := &fakeSender{fail: map[string]error{broken.Email: errors.New("mailbox unavailable")}}
+	svc := NewEmailService(config.MailConfig{Enabled: true, DigestHour: 9}, repo, nil, sender, nil)
+
+	sent, err := svc.SendDigests(context.Background(), now)
+	if err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+	if sent != 2 {
+		t.Fatalf("sent = %d, want 2", sent)
+	}
+	if len(sender.sent) != 2 {
+		t.Fatalf("sender got %d messages, want 2", len(sender.sent))
+	}
+
+	first, second := sender.sent[0], sender.sent[1]
+	if first.To != alice.Email || second.To != bob.Email {
+		t.Fatalf("recipients = %s, %s", first.To, second.To)
+	}
+	if !strings.Contains(first.Subject, "每日通知汇总") {
+		t.Fatalf("subject = %q", first.Subject)
+	}
+	if !strings.Contains(first.Text, "任务 A 已被领取") || !strings.Contains(first.Text, "任务 B 已提交验收") {
+		t.Fatalf("alice digest missing items:\n%s", first.Text)
+	}
+	if strings.Index(first.Text, "任务 A") > strings.Index(first.Text, "任务 B") {
+		t.Fatalf("alice digest not in chronological order:\n%s", first.Text)
+	}
+	if strings.Contains(first.Text, "上上期") || strings.Contains(first.Text, "任务 C") {
+		t.Fatalf("alice digest contains foreign items:\n%s", first.Text)
+	}
+	if !strings.Contains(second.Text, "任务 C 即将截止") || strings.Contains(second.Text, "任务 A") {
+		t.Fatalf("bob digest has wrong items:\n%s", second.Text)
+	}
+
+	if len(repo.marked) != 2 || repo.marked[0].userID != alice.UserID || repo.marked[1].userID != bob.UserID {
+		t.Fatalf("marked = %+v", repo.marked)
+	}
+	if want := periodStart.Add(-24 * time.Hour); !repo.marked[0].since.Equal(want) || !repo.marked[0].until.Equal(now) {
+		t.Fatalf("marked range = %v..%v, want %v..%v", repo.marked[0].since, repo.marked[0].until, want, now)
+	}
+	if len(repo.reset) != 1 || repo.reset[0] != broken.UserID {
+		t.Fatalf("reset = %v, want only the failed recipient", repo.reset)
+	}
+}
+
+func TestSendDigestsSkipsFailingRecipientUntilRetry(t *testing.T) {
+	now := time.Date(2024, 3, 5, 9, 5, 0, 0, time.Local)
+	broken := digestRecipient("broken")
+	next := digestRecipient("next")
+	repo := &fakeDigestRepo{
+		queue: []repository.DigestRecipient{broken, next},
+		items: map[uuid.UUID][]notification.Notification{
+			broken.UserID: {{ID: 1, UserID: broken.UserID, Title: "任务 A", CreatedAt: now.Add(-time.Hour)}},
+			next.UserID:   {{ID: 2, UserID: next.UserID, Title: "任务 B", CreatedAt: now.Add(-time.Hour)}},
+		},
+	}
+	sender := &fakeSender{fail: map[string]error{broken.Email: errors.New("smtp unavailable")}}
+	svc := NewEmailService(config.MailConfig{Enabled: true, DigestHour: 9}, repo, nil, sender, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	sent, err := svc.SendDigests(ctx, now)
+	if err != nil {
+		t.Fatalf("SendDigests: %v (after %d claims)", err, repo.claims)
+	}
+	if sent != 1 || len(sender.sent) != 1 || sender.sent[0].To != next.Email {
+		t.Fatalf("sent = %d, messages = %+v; want only %s", sent, sender.sent, next.Email)
+	}
+	if len(repo.reset) != 1 {
+		t.Fatalf("failing recipient reset %d times in one pass, want 1", len(repo.reset))
+	}
+	if at := repo.retryAt[broken.UserID]; !at.Equal(now.Add(digestRetryDelay)) {
+		t.Fatalf("retry at = %v, want %v", at, now.Add(digestRetryDelay))
+	}
+
+	// 到达重试时间后再次领取并尝试发送。
+	sender.fail = nil
+	sent, err = svc.SendDigests(context.Background(), now.Add(digestRetryDelay))
+	if err != nil || sent != 1 || sender.sent[len(sender.sent)-1].To != broken.Email {
+		t.Fatalf("retry pass = %d, %v; want digest to %s", sent, err, broken.Email)
+	}
+}
+
+func TestSendDigestsListsOmittedCount(t *testing.T) {
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.Local)
+	rcpt := digestRecipient("busy")
+	items := make([]notification.Notification, 0, digestMaxItems+3)
+	for i := range digestMaxItems + 3 {
+		items = append(items, notification.Notification{ID: int64(i + 1), UserID: rcpt.UserID, Title: "通知", CreatedAt: now.Add(-time.Hour)})
+	}
+	repo := &fakeDigestRepo{
+		queue: []repository.DigestRecipient{rcpt},
+		items: map[uuid.UUID][]notification.Notification{rcpt.UserID: items},
+	}
+	sender := &fakeSender{}
+	svc := NewEmailService(config.MailConfig{Enabled: true, DigestHour: 9}, repo, nil, sender, nil)
+
+	if _, err := svc.SendDigests(context.Background(), now); err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("sender got %d messages, want 1", len(sender.sent))
+	}
+	if !strings.Contains(sender.sent[0].Text, "另有 3 条通知未列出") {
+		t.Fatalf("digest does not mention omitted items:\n%s", sender.sent[0].Text)
+	}
+}
+
+type fakeEmailUsers struct {
+	repository.UserRepository
+	usr user.User
+}
+
+func (f *fakeEmailUsers) GetByID(_ context.Context, _ uuid.UUID) (user.User, error) {
+	return f.usr, nil
+}
+
+func TestSendTestCooldown(t *testing.T) {
+	usr := user.User{ID: uuid.New(), DisplayName: "tester", Email: "tester@example.com"}
+	sender := &fakeSender{}
+	svc := NewEmailService(config.MailConfig{Enabled: true}, nil, &fakeEmailUsers{usr: usr}, sender, nil)
+
+	if err := svc.SendTest(context.Background(), usr.ID); err != nil {
+		t.Fatalf("first SendTest: %v", err)
+	}
+	if err := svc.SendTest(context.Background(), usr.ID); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("second SendTest = %v, want ErrRateLimited", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("sender got %d messages, want 1", len(sender.sent))
+	}
+
+	// 其他用户不受影响，冷却期过后可以再次发送。
+	if !svc.allowTest(uuid.New(), time.Now()) {
+		t.Fatal("cooldown applied to a different user")
+	}
+	if !svc.allowTest(usr.ID, time.Now().Add(testEmailCooldown)) {
+		t.Fatal("cooldown still applied after it expired")
+	}
+}
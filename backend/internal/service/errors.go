@@ -13,4 +13,16 @@ var (
 	ErrNotFound = errors.New("not found")
 	// ErrValidation 表示请求参数不符合要求。
 	ErrValidation = errors.New("validation error")
+	// ErrConflict 表示资源状态已被并发修改或不允许当前操作。
+	ErrConflict = errors.New("conflict")
+	// ErrClaimLimitReached 表示成员持有的任务数已达上限。
+	ErrClaimLimitReached = errors.New("claim limit reached")
+	// ErrClaimCooldown 表示成员释放任务后仍处于冷却期。
+	ErrClaimCooldown = errors.New("claim cooldown")
+	// ErrTaskBlocked 表示任务的前置任务尚未完成。
+	ErrTaskBlocked = errors.New("task blocked")
+	// ErrPayloadTooLarge 表示上传内容超过大小限制。
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrRateLimited 表示操作过于频繁。
+	ErrRateLimited = errors.New("rate limited")
 )
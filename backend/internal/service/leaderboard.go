@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/leaderboard"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// LeaderboardService 负责按周期、标签统计积分排名。
+type LeaderboardService struct {
+	repo repository.LeaderboardRepository
+	log  *zap.Logger
+	now  func() time.Time
+}
+
+// LeaderboardInput 控制排行榜查询。
+type LeaderboardInput struct {
+	Period leaderboard.Period
+	Tag    string
+	Limit  int
+}
+
+// LeaderboardResult 返回排行榜及统计区间。
+type LeaderboardResult struct {
+	Period leaderboard.Period
+	Tag    string
+	From   *time.Time
+	To     *time.Time
+	Items  []leaderboard.Standing
+}
+
+// NewLeaderboardService 构造排行榜服务。
+func NewLeaderboardService(repo repository.LeaderboardRepository, log *zap.Logger) *LeaderboardService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &LeaderboardService{repo: repo, log: log, now: time.Now}
+}
+
+// GetLeaderboard 返回指定周期的排名，并附带相对上一周期的名次变化。
+func (s *LeaderboardService) GetLeaderboard(ctx context.Context, input LeaderboardInput) (LeaderboardResult, error) {
+	period := input.Period
+	if period == "" {
+		period = leaderboard.PeriodMonth
+	}
+	limit := input.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	tag := strings.TrimSpace(input.Tag)
+
+	from, to, prevFrom, err := periodWindow(period, s.now())
+	if err != nil {
+		return LeaderboardResult{}, err
+	}
+
+	result := LeaderboardResult{Period: period, Tag: tag}
+
+	if period == leaderboard.PeriodAll {
+		items, err := s.repo.Standings(ctx, repository.LeaderboardFilter{Tag: tag, Limit: limit})
+		if err != nil {
+			return LeaderboardResult{}, err
+		}
+		result.Items = items
+		return result, nil
+	}
+
+	items, err := s.repo.Standings(ctx, repository.LeaderboardFilter{From: &from, To: &to, Tag: tag, Limit: limit})
+	if err != nil {
+		return LeaderboardResult{}, err
+	}
+
+	// 上一周期不限条数，保证当前榜单中每位用户都能找到原名次。
+	previous, err := s.repo.Standings(ctx, repository.LeaderboardFilter{From: &prevFrom, To: &from, Tag: tag})
+	if err != nil {
+		return LeaderboardResult{}, err
+	}
+	prevRanks := make(map[uuid.UUID]int, len(previous))
+	for _, st := range previous {
+		prevRanks[st.UserID] = st.Rank
+	}
+	for i := range items {
+		if rank, ok := prevRanks[items[i].UserID]; ok {
+			r := rank
+			items[i].PreviousRank = &r
+		}
+	}
+
+	result.From = &from
+	result.To = &to
+	result.Items = items
+	return result, nil
+}
+
+// periodWindow 计算统计周期的起止时间以及上一周期的起点（上一周期终点即本周期起点）。
+// 学期按每年 2 月 1 日（春季）与 8 月 1 日（秋季）划分。
+func periodWindow(period leaderboard.Period, now time.Time) (from, to, prevFrom time.Time, err error) {
+	loc := now.Location()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch period {
+	case leaderboard.PeriodAll:
+		return time.Time{}, time.Time{}, time.Time{}, nil
+	case leaderboard.PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		from = day.AddDate(0, 0, -offset)
+		return from, from.AddDate(0, 0, 7), from.AddDate(0, 0, -7), nil
+	case leaderboard.PeriodMonth:
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		return from, from.AddDate(0, 1, 0), from.AddDate(0, -1, 0), nil
+	case leaderboard.PeriodSemester:
+		switch {
+		case now.Month() >= time.August:
+			from = time.Date(now.Year(), time.August, 1, 0, 0, 0, 0, loc)
+		case now.Month() >= time.February:
+			from = time.Date(now.Year(), time.February, 1, 0, 0, 0, 0, loc)
+		default:
+			from = time.Date(now.Year()-1, time.August, 1, 0, 0, 0, 0, loc)
+		}
+		return from, from.AddDate(0, 6, 0), from.AddDate(0, -6, 0), nil
+	default:
+		return time.Time{}, time.Time{}, time.Time{}, fmt.Errorf("%w: unsupported period", ErrValidation)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/ledger"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// LedgerService 提供积分余额与流水查询。
+type LedgerService struct {
+	repo repository.LedgerRepository
+	log  *zap.Logger
+}
+
+// PointsListInput 控制积分流水分页。
+type PointsListInput struct {
+	Page     int
+	PageSize int
+}
+
+// PointsResult 汇总积分余额与分页流水。
+type PointsResult struct {
+	Balance  int64
+	Items    []ledger.Entry
+	Total    int
+	Page     int
+	PageSize int
+}
+
+// PointsAdjustInput 描述管理员发起的积分调整。
+type PointsAdjustInput struct {
+	UserID     uuid.UUID
+	OperatorID uuid.UUID
+	Kind       ledger.AdjustmentKind
+	Amount     int64
+	TaskID     *uuid.UUID
+	Reason     string
+}
+
+// PointsAdjustResult 返回调整记录与调整后的余额。
+type PointsAdjustResult struct {
+	Adjustment ledger.Adjustment
+	Balance    int64
+}
+
+// NewLedgerService 构造积分服务。
+func NewLedgerService(repo repository.LedgerRepository, log *zap.Logger) *LedgerService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &LedgerService{repo: repo, log: log}
+}
+
+// GetUserPoints 返回用户的积分余额及流水明细。
+func (s *LedgerService) GetUserPoints(ctx context.Context, userID uuid.UUID, input PointsListInput) (PointsResult, error) {
+	if userID == uuid.Nil {
+		return PointsResult{}, ErrUnauthorized
+	}
+
+	pageSize := input.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	page := input.Page
+	if page <= 0 {
+		page = 1
+	}
+	offset := (page - 1) * pageSize
+
+	balance, err := s.repo.Balance(ctx, userID)
+	if err != nil {
+		return PointsResult{}, err
+	}
+
+	items, total, err := s.repo.ListEntries(ctx, userID, pageSize, offset)
+	if err != nil {
+		return PointsResult{}, err
+	}
+
+	return PointsResult{
+		Balance:  balance,
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// AdjustPoints 由管理员发放奖励、扣罚积分或撤销误发的任务奖励。
+func (s *LedgerService) AdjustPoints(ctx context.Context, input PointsAdjustInput) (PointsAdjustResult, error) {
+	if input.OperatorID == uuid.Nil {
+		return PointsAdjustResult{}, ErrUnauthorized
+	}
+	if input.UserID == uuid.Nil {
+		return PointsAdjustResult{}, fmt.Errorf("%w: user required", ErrValidation)
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return PointsAdjustResult{}, fmt.Errorf("%w: reason required", ErrValidation)
+	}
+	if utf8.RuneCountInString(reason) > 200 {
+		return PointsAdjustResult{}, fmt.Errorf("%w: reason too long", ErrValidation)
+	}
+
+	amount := input.Amount
+	switch input.Kind {
+	case ledger.AdjustmentBonus:
+		if amount <= 0 {
+			return PointsAdjustResult{}, fmt.Errorf("%w: amount must be positive", ErrValidation)
+		}
+	case ledger.AdjustmentPenalty:
+		if amount <= 0 {
+			return PointsAdjustResult{}, fmt.Errorf("%w: amount must be positive", ErrValidation)
+		}
+		amount = -amount
+	case ledger.AdjustmentReversal:
+		if input.TaskID == nil || *input.TaskID == uuid.Nil {
+			return PointsAdjustResult{}, fmt.Errorf("%w: task required for reversal", ErrValidation)
+		}
+		// 撤销金额由仓储根据已发放奖励计算。
+		amount = 0
+	default:
+		return PointsAdjustResult{}, fmt.Errorf("%w: unsupported adjustment kind", ErrValidation)
+	}
+
+	adj, err := s.repo.Adjust(ctx, repository.AdjustmentInput{
+		UserID:     input.UserID,
+		OperatorID: input.OperatorID,
+		Kind:       input.Kind,
+		Amount:     amount,
+		TaskID:     input.TaskID,
+		Reason:     reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return PointsAdjustResult{}, ErrNotFound
+		case errors.Is(err, repository.ErrNothingToReverse):
+			return PointsAdjustResult{}, fmt.Errorf("%w: no reward to reverse for task", ErrValidation)
+		}
+		return PointsAdjustResult{}, err
+	}
+
+	balance, err := s.repo.Balance(ctx, input.UserID)
+	if err != nil {
+		return PointsAdjustResult{}, err
+	}
+
+	return PointsAdjustResult{Adjustment: adj, Balance: balance}, nil
+}
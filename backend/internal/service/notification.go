@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"backend/internal/domain/notification"
+	"backend/internal/domain/outbox"
+	"backend/internal/domain/task"
+	"backend/internal/events"
+	"backend/internal/repository"
+)
+
+// NotificationService 负责生成站内通知并提供收件箱查询。
+// 任务流转通知经发件箱生成，失败时随事件重试；评论提及通知写入失败只记录日志，不影响触发它的业务操作。
+type NotificationService struct {
+	repo  repository.NotificationRepository
+	tasks repository.TaskRepository
+	log   *zap.Logger
+}
+
+// NotificationListResult 封装通知分页结果。
+type NotificationListResult struct {
+	Items    []notification.Notification
+	Total    int
+	Unread   int
+	Page     int
+	PageSize int
+}
+
+// NewNotificationService 构造通知服务。
+func NewNotificationService(repo repository.NotificationRepository, tasks repository.TaskRepository, log *zap.Logger) *NotificationService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &NotificationService{repo: repo, tasks: tasks, log: log}
+}
+
+// ListNotifications 返回用户的通知，unreadOnly 为 true 时只返回未读。
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, pageSize int) (NotificationListResult, error) {
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	items, total, err := s.repo.List(ctx, userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return NotificationListResult{}, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return NotificationListResult{}, err
+	}
+
+	return NotificationListResult{Items: items, Total: total, Unread: unread, Page: page, PageSize: pageSize}, nil
+}
+
+// UnreadCount 返回用户未读通知数量。
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead 将单条通知标记为已读。
+func (s *NotificationService) MarkRead(ctx context.Context, userID uuid.UUID, id int64) error {
+	if err := s.repo.MarkRead(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead 将用户全部通知标记为已读。
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// notificationTaskEvent 为发件箱任务事件中生成通知所需的字段。
+type notificationTaskEvent struct {
+	TaskID     uuid.UUID  `json:"taskId"`
+	Title      string     `json:"title"`
+	CreatedBy  uuid.UUID  `json:"createdBy"`
+	AssigneeID *uuid.UUID `json:"assigneeId"`
+	ActorID    *uuid.UUID `json:"actorId"`
+	Comment    string     `json:"comment"`
+}
+
+// HandleTaskEvent 作为发件箱的分发目标，为领取、提交与验收事件生成站内通知：
+// 领取通知创建者，提交通知创建者与管理员，验收结果通知执行人，均同时通知关注者，操作者本人不会收到。
+// 通知以发件箱事件 ID 去重，写入失败时返回错误由发件箱重试，重试不会重复通知。
+func (s *NotificationService) HandleTaskEvent(ctx context.Context, msg outbox.Message) error {
+	var evt notificationTaskEvent
+	if err := json.Unmarshal(msg.Payload, &evt); err != nil {
+		s.log.Warn("task notification skipped: invalid payload", zap.Int64("event_id", msg.ID), zap.Error(err))
+		return nil
+	}
+
+	base := notification.Notification{TaskID: &evt.TaskID, ActorID: evt.ActorID}
+	var recipients []uuid.UUID
+	switch msg.Topic {
+	case events.TypeTaskClaimed:
+		base.Kind = notification.KindTaskClaimed
+		base.Title = fmt.Sprintf("任务「%s」已被领取", evt.Title)
+		recipients = append(recipients, evt.CreatedBy)
+	case events.TypeTaskSubmitted:
+		base.Kind = notification.KindTaskSubmitted
+		base.Title = fmt.Sprintf("任务「%s」已提交验收", evt.Title)
+		admins, err := s.repo.ListAdminIDs(ctx)
+		if err != nil {
+			return err
+		}
+		recipients = append(append(recipients, evt.CreatedBy), admins...)
+	case events.TypeTaskCompleted, events.TypeTaskRejected:
+		base.Kind = notification.KindTaskApproved
+		base.Title = fmt.Sprintf("任务「%s」已通过验收", evt.Title)
+		if msg.Topic == events.TypeTaskRejected {
+			base.Kind = notification.KindTaskRejected
+			base.Title = fmt.Sprintf("任务「%s」未通过验收", evt.Title)
+		}
+		base.Body = evt.Comment
+		if evt.AssigneeID != nil {
+			recipients = append(recipients, *evt.AssigneeID)
+		}
+	default:
+		return nil
+	}
+
+	watchers, err := s.tasks.ListWatchers(ctx, evt.TaskID)
+	if err != nil {
+		return err
+	}
+	base.DedupKey = fmt.Sprintf("event:%d", msg.ID)
+
+	var exclude uuid.UUID
+	if evt.ActorID != nil {
+		exclude = *evt.ActorID
+	}
+	_, err = s.repo.Create(ctx, fanOut(base, exclude, append(recipients, watchers...)))
+	return err
+}
+
+// CommentMentioned 通知评论中被 @ 的用户。
+func (s *NotificationService) CommentMentioned(ctx context.Context, comment task.Comment) {
+	if s == nil || len(comment.Mentions) == 0 {
+		return
+	}
+	tk, err := s.tasks.GetByID(ctx, comment.TaskID)
+	if err != nil {
+		s.log.Warn("load task for mention notification failed", zap.String("task_id", comment.TaskID.String()), zap.Error(err))
+		return
+	}
+
+	recipients := make([]uuid.UUID, 0, len(comment.Mentions))
+	for _, mention := range comment.Mentions {
+		recipients = append(recipients, mention.UserID)
+	}
+	// 提及只通知被点名的人，不扇出给关注者。
+	s.publish(ctx, notification.Notification{
+		Kind:    notification.KindCommentMention,
+		TaskID:  &tk.ID,
+		ActorID: &comment.AuthorID,
+		Title:   fmt.Sprintf("%s 在任务「%s」中提到了你", comment.AuthorName, tk.Title),
+		Body:    commentExcerpt(comment.BodyPlain),
+	}, comment.AuthorID, recipients)
+}
+
+// NotifyDeadlines 提醒执行人截止时间将在 window 内到达，每次领取的每个截止时间只提醒一次。
+func (s *NotificationService) NotifyDeadlines(ctx context.Context, now time.Time, window time.Duration) (int, error) {
+	if window <= 0 {
+		return 0, nil
+	}
+	now = now.UTC()
+	claims, err := s.tasks.ListDueSoonClaims(ctx, now, now.Add(window), 200)
+	if err != nil {
+		return 0, err
+	}
+
+	items := make([]notification.Notification, 0, len(claims))
+	for _, claim := range claims {
+		taskID := claim.TaskID
+		items = append(items, notification.Notification{
+			UserID: claim.UserID,
+			Kind:   notification.KindDeadlineApproach,
+			TaskID: &taskID,
+			Title:  "领取的任务即将截止",
+			Body:   fmt.Sprintf("截止时间：%s", claim.Deadline.Format(time.RFC3339)),
+			// 去重键格式需与 ListDueSoonClaims 中排除已提醒领取的条件一致。
+			DedupKey: fmt.Sprintf("deadline:%s:%s:%d", claim.TaskID, claim.UserID, claim.Deadline.Unix()),
+		})
+	}
+	return s.repo.Create(ctx, items)
+}
+
+func (s *NotificationService) publish(ctx context.Context, base notification.Notification, exclude uuid.UUID, recipients []uuid.UUID) {
+	if _, err := s.repo.Create(ctx, fanOut(base, exclude, recipients)); err != nil {
+		s.log.Warn("create notifications failed", zap.String("kind", string(base.Kind)), zap.Error(err))
+	}
+}
+
+// fanOut 为每位收件人复制一条通知，跳过空 ID、重复的收件人与 exclude。
+func fanOut(base notification.Notification, exclude uuid.UUID, recipients []uuid.UUID) []notification.Notification {
+	seen := make(map[uuid.UUID]struct{}, len(recipients))
+	items := make([]notification.Notification, 0, len(recipients))
+	for _, id := range recipients {
+		if id == uuid.Nil || id == exclude {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		item := base
+		item.UserID = id
+		items = append(items, item)
+	}
+	return items
+}
+
+func commentExcerpt(plain string) string {
+	runes := []rune(plain)
+	if len(runes) <= 140 {
+		return plain
+	}
+	return string(runes[:140]) + "…"
+}
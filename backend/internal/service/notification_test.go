@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/notification"
+	"backend/internal/domain/outbox"
+	"backend/internal/events"
+	"backend/internal/repository"
+)
+
+// fakeNotificationRepo 记录写入的通知；只实现任务事件通知用到的方法。
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+
+	admins  []uuid.UUID
+	created []notification.Notification
+	fail    error
+}
+
+func (f *fakeNotificationRepo) ListAdminIDs(context.Context) ([]uuid.UUID, error) {
+	return f.admins, nil
+}
+
+func (f *fakeNotificationRepo) Create(_ context.Context, items []notification.Notification) (int, error) {
+	if f.fail != nil {
+		return 0, f.fail
+	}
+	f.created = append(f.created, items...)
+	return len(items), nil
+}
+
+type fakeWatcherRepo struct {
+	repository.TaskRepository
+
+	watchers []uuid.UUID
+}
+
+func (f *fakeWatcherRepo) ListWatchers(context.Context, uuid.UUID) ([]uuid.UUID, error) {
+	return f.watchers, nil
+}
+
+func taskEventMessage(t *testing.T, topic string, payload map[string]any) outbox.Message {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outbox.Message{ID: 42, Topic: topic, Payload: raw}
+}
+
+func recipientsOf(items []notification.Notification) map[uuid.UUID]notification.Notification {
+	byUser := make(map[uuid.UUID]notification.Notification, len(items))
+	for _, item := range items {
+		byUser[item.UserID] = item
+	}
+	return byUser
+}
+
+func TestHandleTaskEventRejectedNotifiesAssigneeAndWatchers(t *testing.T) {
+	taskID, creator, assignee, reviewer, watcher := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeNotificationRepo{}
+	svc := NewNotificationService(repo, &fakeWatcherRepo{watchers: []uuid.UUID{reviewer, watcher, assignee}}, nil)
+
+	msg := taskEventMessage(t, events.TypeTaskRejected, map[string]any{
+		"taskId":     taskID,
+		"title":      "整理周报",
+		"createdBy":  creator,
+		"assigneeId": assignee,
+		"actorId":    reviewer,
+		"comment":    "缺少数据来源",
+	})
+	if err := svc.HandleTaskEvent(context.Background(), msg); err != nil {
+		t.Fatalf("HandleTaskEvent: %v", err)
+	}
+
+	got := recipientsOf(repo.created)
+	if len(repo.created) != 2 || got[assignee].UserID != assignee || got[watcher].UserID != watcher {
+		t.Fatalf("recipients = %+v, want assignee and watcher only", repo.created)
+	}
+	item := got[assignee]
+	if item.Kind != notification.KindTaskRejected || item.Body != "缺少数据来源" || item.DedupKey != "event:42" {
+		t.Fatalf("notification = %+v", item)
+	}
+	if item.TaskID == nil || *item.TaskID != taskID || item.ActorID == nil || *item.ActorID != reviewer {
+		t.Fatalf("notification refs = %+v", item)
+	}
+}
+
+func TestHandleTaskEventSubmittedNotifiesCreatorAndAdmins(t *testing.T) {
+	creator, assignee, admin := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeNotificationRepo{admins: []uuid.UUID{admin, creator}}
+	svc := NewNotificationService(repo, &fakeWatcherRepo{}, nil)
+
+	msg := taskEventMessage(t, events.TypeTaskSubmitted, map[string]any{
+		"taskId":    uuid.New(),
+		"title":     "整理周报",
+		"createdBy": creator,
+		"actorId":   assignee,
+	})
+	if err := svc.HandleTaskEvent(context.Background(), msg); err != nil {
+		t.Fatalf("HandleTaskEvent: %v", err)
+	}
+	got := recipientsOf(repo.created)
+	if len(repo.created) != 2 || got[creator].Kind != notification.KindTaskSubmitted || got[admin].Kind != notification.KindTaskSubmitted {
+		t.Fatalf("recipients = %+v, want creator and admin", repo.created)
+	}
+}
+
+func TestHandleTaskEventReturnsWriteErrorForRetry(t *testing.T) {
+	repo := &fakeNotificationRepo{fail: errors.New("db down")}
+	svc := NewNotificationService(repo, &fakeWatcherRepo{}, nil)
+
+	msg := taskEventMessage(t, events.TypeTaskClaimed, map[string]any{
+		"taskId":    uuid.New(),
+		"createdBy": uuid.New(),
+		"actorId":   uuid.New(),
+	})
+	if err := svc.HandleTaskEvent(context.Background(), msg); err == nil {
+		t.Fatal("expected write error to be returned so the outbox retries")
+	}
+
+	repo.fail = nil
+	if err := svc.HandleTaskEvent(context.Background(), taskEventMessage(t, events.TypeTaskUpdated, map[string]any{"taskId": uuid.New()})); err != nil || len(repo.created) != 0 {
+		t.Fatalf("task.updated = %v, %+v; want ignored", err, repo.created)
+	}
+}
@@ -2,27 +2,62 @@ package service
 
 import (
 	"backend/internal/config"
+	"backend/internal/mail"
 	"backend/internal/repository"
+	"backend/internal/storage"
 
 	"go.uber.org/zap"
 )
 
 // Registry 汇总所有业务服务。
 type Registry struct {
-	Auth  *AuthService
-	Users *UserService
-	Tasks *TaskService
+	Auth          *AuthService
+	Users         *UserService
+	Tasks         *TaskService
+	Ledger        *LedgerService
+	Leaderboard   *LeaderboardService
+	Audit         *AuditService
+	Templates     *TemplateService
+	Schedules     *ScheduleService
+	Comments      *CommentService
+	Attachments   *AttachmentService
+	Notifications *NotificationService
+	Webhooks      *WebhookService
+	Emails        *EmailService
+	Chat          *ChatService
 }
 
 // NewRegistry 初始化服务依赖。
-func NewRegistry(cfg config.Config, repos repository.Registry, log *zap.Logger) Registry {
+func NewRegistry(cfg config.Config, repos repository.Registry, blobs storage.BlobStore, log *zap.Logger) Registry {
 	userService := NewUserService(cfg.Auth, repos.User, log)
 	authService := NewAuthService(cfg.Auth, cfg.Campus, repos.User, log)
-	taskService := NewTaskService(repos.Task, log)
+	notificationService := NewNotificationService(repos.Notification, repos.Task, log)
+	taskService := NewTaskService(cfg.Claim, repos.Task, log)
+	ledgerService := NewLedgerService(repos.Ledger, log)
+	leaderboardService := NewLeaderboardService(repos.Leaderboard, log)
+	auditService := NewAuditService(repos.Audit, log)
+	templateService := NewTemplateService(repos.Template, taskService, log)
+	scheduleService := NewScheduleService(repos.Schedule, taskService, log)
+	commentService := NewCommentService(repos.Comment, notificationService, log)
+	attachmentService := NewAttachmentService(repos.Attachment, taskService, blobs, cfg.Storage.MaxUploadBytes, log)
+	webhookService := NewWebhookService(cfg.Webhook, repos.Webhook, log)
+	chatService := NewChatService(cfg.Chat, repos.ChatChannel, log)
+	emailService := NewEmailService(cfg.Mail, repos.Notification, repos.User, mail.NewSMTPSender(cfg.Mail), log)
 
 	return Registry{
-		Auth:  authService,
-		Users: userService,
-		Tasks: taskService,
+		Auth:          authService,
+		Users:         userService,
+		Tasks:         taskService,
+		Ledger:        ledgerService,
+		Leaderboard:   leaderboardService,
+		Audit:         auditService,
+		Templates:     templateService,
+		Schedules:     scheduleService,
+		Comments:      commentService,
+		Attachments:   attachmentService,
+		Notifications: notificationService,
+		Webhooks:      webhookService,
+		Emails:        emailService,
+		Chat:          chatService,
 	}
 }
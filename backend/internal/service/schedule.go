@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"backend/internal/domain/task"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// cronParser 解析标准五段 cron 表达式，同时支持 @daily、@weekly 等描述符。
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ScheduleService 管理周期任务计划并按计划自动发布任务。
+type ScheduleService struct {
+	repo  repository.ScheduleRepository
+	tasks *TaskService
+	log   *zap.Logger
+	now   func() time.Time
+}
+
+// ScheduleInput 描述创建周期计划所需字段。
+type ScheduleInput struct {
+	Name            string
+	CronExpr        string
+	Timezone        string
+	Title           string
+	DescriptionHTML string
+	Bounty          int64
+	Priority        task.Priority
+	Tags            []string
+	DeadlineOffset  time.Duration
+	Paused          bool
+	CreatedBy       uuid.UUID
+}
+
+// ScheduleUpdateInput 描述周期计划可更新字段，nil 表示保持不变。
+type ScheduleUpdateInput struct {
+	ID              uuid.UUID
+	ActorID         uuid.UUID
+	Name            *string
+	CronExpr        *string
+	Timezone        *string
+	Title           *string
+	DescriptionHTML *string
+	Bounty          *int64
+	Priority        *task.Priority
+	Tags            *[]string
+	DeadlineOffset  *time.Duration
+}
+
+// NewScheduleService 构造周期任务计划服务。
+func NewScheduleService(repo repository.ScheduleRepository, tasks *TaskService, log *zap.Logger) *ScheduleService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &ScheduleService{repo: repo, tasks: tasks, log: log, now: time.Now}
+}
+
+// ListSchedules 返回全部周期计划。
+func (s *ScheduleService) ListSchedules(ctx context.Context) ([]task.Schedule, error) {
+	return s.repo.List(ctx)
+}
+
+// GetSchedule 返回周期计划详情。
+func (s *ScheduleService) GetSchedule(ctx context.Context, id uuid.UUID) (task.Schedule, error) {
+	sch, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Schedule{}, ErrNotFound
+	}
+	return sch, err
+}
+
+// CreateSchedule 新建周期计划，未暂停时立即计算下一次触发时间。
+func (s *ScheduleService) CreateSchedule(ctx context.Context, input ScheduleInput) (task.Schedule, error) {
+	sch := task.Schedule{
+		Name:            strings.TrimSpace(input.Name),
+		CronExpr:        strings.TrimSpace(input.CronExpr),
+		Timezone:        strings.TrimSpace(input.Timezone),
+		Title:           strings.TrimSpace(input.Title),
+		DescriptionHTML: input.DescriptionHTML,
+		Bounty:          input.Bounty,
+		Priority:        input.Priority,
+		Tags:            s.tasks.normalizeTags(input.Tags),
+		DeadlineOffset:  input.DeadlineOffset,
+		Paused:          input.Paused,
+		CreatedBy:       input.CreatedBy,
+	}
+	if sch.Timezone == "" {
+		sch.Timezone = "UTC"
+	}
+	if sch.Priority == "" {
+		sch.Priority = task.PriorityMedium
+	}
+	if err := s.prepare(&sch); err != nil {
+		return task.Schedule{}, err
+	}
+	return s.repo.Create(ctx, sch)
+}
+
+// UpdateSchedule 更新周期计划，修改后按新规则从当前时间重新计算下一次触发。
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, input ScheduleUpdateInput) (task.Schedule, error) {
+	sch, err := s.GetSchedule(ctx, input.ID)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+
+	if input.Name != nil {
+		sch.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.CronExpr != nil {
+		sch.CronExpr = strings.TrimSpace(*input.CronExpr)
+	}
+	if input.Timezone != nil {
+		sch.Timezone = strings.TrimSpace(*input.Timezone)
+	}
+	if input.Title != nil {
+		sch.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.DescriptionHTML != nil {
+		sch.DescriptionHTML = *input.DescriptionHTML
+	}
+	if input.Bounty != nil {
+		sch.Bounty = *input.Bounty
+	}
+	if input.Priority != nil {
+		sch.Priority = *input.Priority
+	}
+	if input.Tags != nil {
+		sch.Tags = s.tasks.normalizeTags(*input.Tags)
+	}
+	if input.DeadlineOffset != nil {
+		sch.DeadlineOffset = *input.DeadlineOffset
+	}
+	if err := s.prepare(&sch); err != nil {
+		return task.Schedule{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, sch, input.ActorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Schedule{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// DeleteSchedule 删除周期计划，已发布的任务不受影响。
+func (s *ScheduleService) DeleteSchedule(ctx context.Context, id, actor uuid.UUID) error {
+	err := s.repo.Delete(ctx, id, actor)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// PauseSchedule 暂停周期计划。
+func (s *ScheduleService) PauseSchedule(ctx context.Context, id, actor uuid.UUID) (task.Schedule, error) {
+	updated, err := s.repo.SetPaused(ctx, id, true, nil, actor)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Schedule{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// ResumeSchedule 恢复周期计划，暂停期间错过的触发不会补发。
+func (s *ScheduleService) ResumeSchedule(ctx context.Context, id, actor uuid.UUID) (task.Schedule, error) {
+	sch, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+	spec, loc, err := parseSchedule(sch.CronExpr, sch.Timezone)
+	if err != nil {
+		return task.Schedule{}, err
+	}
+	next := spec.Next(s.now().In(loc)).UTC()
+
+	updated, err := s.repo.SetPaused(ctx, id, false, &next, actor)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Schedule{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// UpcomingOccurrences 返回从当前时间起接下来 count 次触发时间。
+func (s *ScheduleService) UpcomingOccurrences(ctx context.Context, id uuid.UUID, count int) ([]time.Time, error) {
+	if count <= 0 || count > 50 {
+		count = 10
+	}
+	sch, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	spec, loc, err := parseSchedule(sch.CronExpr, sch.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]time.Time, 0, count)
+	cursor := s.now().In(loc)
+	for len(occurrences) < count {
+		cursor = spec.Next(cursor)
+		if cursor.IsZero() {
+			break
+		}
+		occurrences = append(occurrences, cursor)
+	}
+	return occurrences, nil
+}
+
+// scheduleRunTimeout 为一次触发从认领到记录结果的最长时间，超过后视为发布中断并重新发布。
+const scheduleRunTimeout = 10 * time.Minute
+
+// RunDue 发布所有已到期的计划，返回本次发布的任务数。
+// 每次触发先通过唯一的 (schedule_id, occurrence) 认领，多副本同时运行时同一触发只会发布一次；
+// 认领后未能记录结果的触发（例如发布前进程退出）会在超时后重新发布。
+func (s *ScheduleService) RunDue(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	published := 0
+
+	stale, err := s.repo.ReclaimStaleRuns(ctx, now.Add(-scheduleRunTimeout), now, 20)
+	if err != nil {
+		return 0, err
+	}
+	for _, run := range stale {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		sch, err := s.repo.GetByID(ctx, run.ScheduleID)
+		if err != nil {
+			s.log.Error("load schedule for stale run failed", zap.String("schedule_id", run.ScheduleID.String()), zap.Error(err))
+			continue
+		}
+		if s.publish(ctx, sch, run.Occurrence, now) {
+			published++
+		}
+	}
+
+	due, err := s.repo.ListDue(ctx, now, 20)
+	if err != nil {
+		return published, err
+	}
+	for _, sch := range due {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		if sch.NextRunAt == nil {
+			continue
+		}
+		occurrence := *sch.NextRunAt
+
+		spec, loc, err := parseSchedule(sch.CronExpr, sch.Timezone)
+		if err != nil {
+			s.log.Error("invalid schedule", zap.String("schedule_id", sch.ID.String()), zap.Error(err))
+			continue
+		}
+		// 停机期间错过的多次触发只补发最近一次，下一次从当前时间之后计算。
+		next := spec.Next(now.In(loc)).UTC()
+
+		claimed, err := s.repo.ClaimOccurrence(ctx, sch.ID, occurrence, next)
+		if err != nil {
+			s.log.Error("claim schedule occurrence failed", zap.String("schedule_id", sch.ID.String()), zap.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if s.publish(ctx, sch, occurrence, now) {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// publish 为已认领的触发发布任务，返回是否发布成功。任务与触发结果在同一事务内写入，
+// 中途失败不会留下已发布却仍待发布的触发；发布失败时单独记录错误。
+// 截止时间从触发时间起算；补发错过的触发时从当前时间起算，避免任务发布时即已逾期。
+func (s *ScheduleService) publish(ctx context.Context, sch task.Schedule, occurrence, now time.Time) bool {
+	input := TaskCreateInput{
+		Title:           sch.Title,
+		DescriptionHTML: sch.DescriptionHTML,
+		Bounty:          sch.Bounty,
+		Priority:        sch.Priority,
+		Tags:            sch.Tags,
+		CreatedBy:       sch.CreatedBy,
+		Publish:         true,
+		ScheduleRun:     &repository.ScheduleRun{ScheduleID: sch.ID, Occurrence: occurrence},
+	}
+	if sch.DeadlineOffset > 0 {
+		start := occurrence
+		if start.Before(now) {
+			start = now
+		}
+		deadline := start.Add(sch.DeadlineOffset)
+		input.Deadline = &deadline
+	}
+
+	created, err := s.tasks.CreateTask(ctx, input)
+	if errors.Is(err, repository.ErrScheduleRunDone) {
+		// 其他副本已发布该触发。
+		return false
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// 停机导致的失败不记录结果，由超时重新发布处理。
+			return false
+		}
+		s.log.Error("publish scheduled task failed",
+			zap.String("schedule_id", sch.ID.String()),
+			zap.Time("occurrence", occurrence),
+			zap.Error(err),
+		)
+		if recordErr := s.repo.RecordRun(ctx, sch.ID, occurrence, nil, err.Error()); recordErr != nil {
+			s.log.Error("record schedule run failed", zap.Error(recordErr))
+		}
+		return false
+	}
+
+	s.log.Info("scheduled task published",
+		zap.String("schedule_id", sch.ID.String()),
+		zap.String("task_id", created.ID.String()),
+		zap.Time("occurrence", occurrence),
+	)
+	return true
+}
+
+// prepare 校验计划字段，并为未暂停的计划计算下一次触发时间。
+func (s *ScheduleService) prepare(sch *task.Schedule) error {
+	if sch.Name == "" {
+		return fmt.Errorf("%w: name required", ErrValidation)
+	}
+	if utf8.RuneCountInString(sch.Name) > 120 {
+		return fmt.Errorf("%w: name too long", ErrValidation)
+	}
+	if err := s.tasks.validatePayload(sch.Title, sch.DescriptionHTML, sch.Bounty, sch.Priority, sch.DeadlineOffset); err != nil {
+		return err
+	}
+
+	spec, loc, err := parseSchedule(sch.CronExpr, sch.Timezone)
+	if err != nil {
+		return err
+	}
+	next := spec.Next(s.now().In(loc))
+	if next.IsZero() {
+		return fmt.Errorf("%w: cron expression never fires", ErrValidation)
+	}
+
+	sch.NextRunAt = nil
+	if !sch.Paused {
+		utc := next.UTC()
+		sch.NextRunAt = &utc
+	}
+	return nil
+}
+
+func parseSchedule(expr, timezone string) (cron.Schedule, *time.Location, error) {
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, nil, fmt.Errorf("%w: use the timezone field instead of a TZ prefix", ErrValidation)
+	}
+	spec, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid cron expression: %v", ErrValidation, err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid timezone %q", ErrValidation, timezone)
+	}
+	return spec, loc, nil
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"html"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"backend/internal/config"
+	"backend/internal/domain/audit"
 	"backend/internal/domain/task"
 	"backend/internal/domain/user"
 	"backend/internal/repository"
@@ -20,8 +24,9 @@ import (
 
 // TaskService 管理任务的业务逻辑。
 type TaskService struct {
-	repo repository.TaskRepository
-	log  *zap.Logger
+	repo   repository.TaskRepository
+	claims config.ClaimPolicyConfig
+	log    *zap.Logger
 }
 
 // TaskListInput 控制任务查询条件。
@@ -32,6 +37,10 @@ type TaskListInput struct {
 	Page           int
 	PageSize       int
 	AssignedTo     uuid.UUID
+	ParentID       uuid.UUID
+	RootOnly       bool
+	HideBlocked    bool
+	WatchedBy      uuid.UUID
 	IncludeDeleted bool
 }
 
@@ -43,6 +52,13 @@ type TaskListResult struct {
 	PageSize int
 }
 
+// TaskActivityResult 返回任务时间线及提交、退回次数统计。
+type TaskActivityResult struct {
+	Items       []task.Activity
+	SubmitCount int
+	RejectCount int
+}
+
 // TaskExportOptions 控制任务导出范围，为后续生成报表预留扩展位。
 type TaskExportOptions struct {
 	Status         []task.Status
@@ -58,27 +74,41 @@ type TaskCreateInput struct {
 	Deadline        *time.Time
 	Tags            []string
 	CreatedBy       uuid.UUID
-	Publish         bool
+	ParentID        *uuid.UUID
+	// RequireChecklist 为 true 时须勾选全部检查清单条目才能提交验收。
+	RequireChecklist bool
+	Checklist        []string
+	Publish          bool
+	// ScheduleRun 非空时表示由周期计划发布，任务创建与触发结果在同一事务内提交。
+	ScheduleRun *repository.ScheduleRun
 }
 
 // TaskUpdateInput 描述任务更新字段。
 type TaskUpdateInput struct {
-	ID              uuid.UUID
-	Title           *string
-	DescriptionHTML *string
-	Bounty          *int64
-	Priority        *task.Priority
-	Deadline        *time.Time
-	Tags            *[]string
-	Status          *task.Status
+	ID               uuid.UUID
+	ActorID          uuid.UUID
+	Title            *string
+	DescriptionHTML  *string
+	Bounty           *int64
+	Priority         *task.Priority
+	Deadline         *time.Time
+	Tags             *[]string
+	Status           *task.Status
+	RequireChecklist *bool
+}
+
+// TaskSubmissionInput 描述执行人提交验收时附带的说明与链接。
+type TaskSubmissionInput struct {
+	Note  string
+	Links []string
 }
 
 // NewTaskService 构造任务服务。
-func NewTaskService(repo repository.TaskRepository, log *zap.Logger) *TaskService {
+func NewTaskService(claimCfg config.ClaimPolicyConfig, repo repository.TaskRepository, log *zap.Logger) *TaskService {
 	if log == nil {
 		log = zap.NewNop()
 	}
-	return &TaskService{repo: repo, log: log}
+	return &TaskService{repo: repo, claims: claimCfg, log: log}
 }
 
 // ListTasks 返回分页任务数据。
@@ -100,6 +130,10 @@ func (s *TaskService) ListTasks(ctx context.Context, input TaskListInput) (TaskL
 		Limit:          pageSize,
 		Offset:         offset,
 		AssignedTo:     input.AssignedTo,
+		ParentID:       input.ParentID,
+		RootOnly:       input.RootOnly,
+		HideBlocked:    input.HideBlocked,
+		WatchedBy:      input.WatchedBy,
 		IncludeDeleted: input.IncludeDeleted,
 	})
 	if err != nil {
@@ -165,6 +199,10 @@ func (s *TaskService) CreateTask(ctx context.Context, input TaskCreateInput) (ta
 	}
 
 	cleanedTags := s.normalizeTags(input.Tags)
+	checklist, err := normalizeChecklist(input.Checklist)
+	if err != nil {
+		return task.Task{}, err
+	}
 
 	created, err := s.repo.Create(ctx, repository.TaskCreateInput{
 		Title:            strings.TrimSpace(input.Title),
@@ -174,19 +212,17 @@ func (s *TaskService) CreateTask(ctx context.Context, input TaskCreateInput) (ta
 		Priority:         priority,
 		Deadline:         input.Deadline,
 		CreatedBy:        input.CreatedBy,
+		ParentID:         input.ParentID,
+		RequireChecklist: input.RequireChecklist,
+		Checklist:        checklist,
 		Tags:             cleanedTags,
+		Publish:          input.Publish,
+		ScheduleRun:      input.ScheduleRun,
 	})
 	if err != nil {
 		return task.Task{}, err
 	}
 
-	if input.Publish {
-		created, err = s.repo.SetStatus(ctx, created.ID, task.StatusAvailable, input.CreatedBy)
-		if err != nil {
-			return task.Task{}, err
-		}
-	}
-
 	return created, nil
 }
 
@@ -200,7 +236,7 @@ func (s *TaskService) UpdateTask(ctx context.Context, input TaskUpdateInput) (ta
 		return task.Task{}, fmt.Errorf("%w: completed tasks are immutable", ErrForbidden)
 	}
 
-	update := repository.TaskUpdateInput{ID: input.ID}
+	update := repository.TaskUpdateInput{ID: input.ID, ActorID: input.ActorID}
 	if input.Title != nil {
 		title := strings.TrimSpace(*input.Title)
 		if title == "" {
@@ -233,7 +269,14 @@ func (s *TaskService) UpdateTask(ctx context.Context, input TaskUpdateInput) (ta
 		tags := s.normalizeTags(*input.Tags)
 		update.Tags = &tags
 	}
-	if input.Status != nil {
+	if input.RequireChecklist != nil {
+		update.RequireChecklist = input.RequireChecklist
+	}
+	if input.Status != nil && *input.Status != current.Status {
+		// 直接修改状态只允许发布、撤回、归档等无需领取记录的转换，领取与验收须走对应接口。
+		if _, err := task.Resolve(current.Status, *input.Status); err != nil {
+			return task.Task{}, err
+		}
 		update.Status = input.Status
 	}
 
@@ -241,8 +284,8 @@ func (s *TaskService) UpdateTask(ctx context.Context, input TaskUpdateInput) (ta
 }
 
 // DeleteTask 删除指定任务。
-func (s *TaskService) DeleteTask(ctx context.Context, taskID uuid.UUID) error {
-	return s.repo.Delete(ctx, taskID)
+func (s *TaskService) DeleteTask(ctx context.Context, taskID, actor uuid.UUID) error {
+	return s.repo.Delete(ctx, taskID, actor)
 }
 
 // PublishTask 将任务状态切换为可领取。
@@ -255,9 +298,29 @@ func (s *TaskService) ArchiveTask(ctx context.Context, taskID uuid.UUID, actor u
 	return s.repo.SetStatus(ctx, taskID, task.StatusArchived, actor)
 }
 
-// ClaimTask 领取任务。
+// ClaimTask 领取任务，受同时持有数量上限与释放后冷却期约束。
 func (s *TaskService) ClaimTask(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
-	return s.repo.Claim(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: userID})
+	tk, err := s.repo.Claim(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: userID}, repository.ClaimLimits{
+		MaxActive: s.claims.MaxActive,
+		Cooldown:  s.claims.ReleaseCooldown,
+	})
+	if errors.Is(err, repository.ErrClaimLimitReached) {
+		return task.Task{}, fmt.Errorf("%w: %v", ErrClaimLimitReached, err)
+	}
+	if errors.Is(err, repository.ErrClaimCooldown) {
+		return task.Task{}, fmt.Errorf("%w: %v", ErrClaimCooldown, err)
+	}
+	if errors.Is(err, task.ErrBlocked) {
+		return task.Task{}, fmt.Errorf("%w: %v", ErrTaskBlocked, err)
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		return task.Task{}, fmt.Errorf("%w: task not available for claim", ErrConflict)
+	}
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	return tk, nil
 }
 
 // ReleaseTask 释放任务。
@@ -266,12 +329,86 @@ func (s *TaskService) ReleaseTask(ctx context.Context, taskID, userID uuid.UUID)
 }
 
 // SubmitTask 执行人提交任务，等待发布人验收。
-func (s *TaskService) SubmitTask(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
-	return s.repo.Submit(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: userID})
+func (s *TaskService) SubmitTask(ctx context.Context, taskID, userID uuid.UUID, input TaskSubmissionInput) (task.Task, error) {
+	note := strings.TrimSpace(input.Note)
+	if utf8.RuneCountInString(note) > 2000 {
+		return task.Task{}, fmt.Errorf("%w: note too long", ErrValidation)
+	}
+	links, err := normalizeLinks(input.Links)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	tk, err := s.repo.Submit(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: userID, Note: note, Links: links})
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	return tk, nil
+}
+
+// AddTaskDependency 声明 taskID 需在 blockerID 完成后才能领取。
+func (s *TaskService) AddTaskDependency(ctx context.Context, taskID, blockerID, actorID uuid.UUID) (task.Task, error) {
+	if taskID == blockerID {
+		return task.Task{}, fmt.Errorf("%w: task cannot depend on itself", ErrValidation)
+	}
+	tk, err := s.repo.AddDependency(ctx, taskID, blockerID, actorID)
+	switch {
+	case errors.Is(err, repository.ErrDependencyCycle):
+		return task.Task{}, fmt.Errorf("%w: dependency would create a cycle", ErrValidation)
+	case errors.Is(err, repository.ErrNotFound):
+		return task.Task{}, ErrNotFound
+	}
+	return tk, err
 }
 
-// CompleteTask 审核任务提交，标记为完成并发放奖励。
-func (s *TaskService) CompleteTask(ctx context.Context, taskID, actorID uuid.UUID, actorRoles []string) (task.Task, error) {
+// RemoveTaskDependency 解除 taskID 对 blockerID 的依赖。
+func (s *TaskService) RemoveTaskDependency(ctx context.Context, taskID, blockerID, actorID uuid.UUID) (task.Task, error) {
+	tk, err := s.repo.RemoveDependency(ctx, taskID, blockerID, actorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Task{}, ErrNotFound
+	}
+	return tk, err
+}
+
+// WatchTask 关注任务，之后任务动态会通知该用户。
+func (s *TaskService) WatchTask(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
+	return s.repo.Watch(ctx, taskID, userID)
+}
+
+// UnwatchTask 取消关注任务。
+func (s *TaskService) UnwatchTask(ctx context.Context, taskID, userID uuid.UUID) (task.Task, error) {
+	return s.repo.Unwatch(ctx, taskID, userID)
+}
+
+// ListTaskWatchers 返回任务关注者，供通知扇出使用。
+func (s *TaskService) ListTaskWatchers(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.ListWatchers(ctx, taskID)
+}
+
+// ListChildTasks 返回父任务下的全部子任务。
+func (s *TaskService) ListChildTasks(ctx context.Context, parentID uuid.UUID) ([]task.Task, error) {
+	if _, err := s.repo.GetByID(ctx, parentID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	result, err := s.ListTasks(ctx, TaskListInput{ParentID: parentID, PageSize: 100, SortKey: "created_asc"})
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// CompleteTask 审核任务提交，标记为完成并发放奖励，comment 为可选的验收意见。
+// 存在未完成或未归档的子任务时拒绝验收。
+func (s *TaskService) CompleteTask(ctx context.Context, taskID, actorID uuid.UUID, actorRoles []string, comment string) (task.Task, error) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > 2000 {
+		return task.Task{}, fmt.Errorf("%w: comment too long", ErrValidation)
+	}
+
 	tk, err := s.repo.GetByID(ctx, taskID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -293,11 +430,25 @@ func (s *TaskService) CompleteTask(ctx context.Context, taskID, actorID uuid.UUI
 		return task.Task{}, fmt.Errorf("%w: task not awaiting verification", ErrValidation)
 	}
 
-	return s.repo.Complete(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: tk.CurrentAssignee.UserID})
+	assigneeID := tk.CurrentAssignee.UserID
+	completed, err := s.repo.Complete(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: assigneeID, ActorID: actorID, Note: comment})
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	return completed, nil
 }
 
-// RejectTask 审核不通过，退回任务继续执行。
-func (s *TaskService) RejectTask(ctx context.Context, taskID, actorID uuid.UUID, actorRoles []string) (task.Task, error) {
+// RejectTask 审核不通过，退回任务继续执行，必须说明退回原因。
+func (s *TaskService) RejectTask(ctx context.Context, taskID, actorID uuid.UUID, actorRoles []string, comment string) (task.Task, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return task.Task{}, fmt.Errorf("%w: rejection comment required", ErrValidation)
+	}
+	if utf8.RuneCountInString(comment) > 2000 {
+		return task.Task{}, fmt.Errorf("%w: comment too long", ErrValidation)
+	}
+
 	tk, err := s.repo.GetByID(ctx, taskID)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -319,7 +470,13 @@ func (s *TaskService) RejectTask(ctx context.Context, taskID, actorID uuid.UUID,
 		return task.Task{}, fmt.Errorf("%w: task not awaiting verification", ErrValidation)
 	}
 
-	return s.repo.Reject(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: tk.CurrentAssignee.UserID})
+	assigneeID := tk.CurrentAssignee.UserID
+	rejected, err := s.repo.Reject(ctx, repository.TaskAssignmentInput{TaskID: taskID, UserID: assigneeID, ActorID: actorID, Note: comment})
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	return rejected, nil
 }
 
 // GetTask 返回任务详情。
@@ -327,6 +484,85 @@ func (s *TaskService) GetTask(ctx context.Context, taskID uuid.UUID) (task.Task,
 	return s.repo.GetByID(ctx, taskID)
 }
 
+// GetTaskActivity 返回任务从创建到验收的完整时间线。
+func (s *TaskService) GetTaskActivity(ctx context.Context, taskID uuid.UUID) (TaskActivityResult, error) {
+	if _, err := s.repo.GetByID(ctx, taskID); err != nil {
+		return TaskActivityResult{}, err
+	}
+
+	items, err := s.repo.ListActivity(ctx, taskID)
+	if err != nil {
+		return TaskActivityResult{}, err
+	}
+
+	result := TaskActivityResult{Items: items}
+	for _, item := range items {
+		switch audit.Action(item.Kind) {
+		case audit.ActionTaskSubmit:
+			result.SubmitCount++
+		case audit.ActionTaskReject:
+			result.RejectCount++
+		}
+	}
+	return result, nil
+}
+
+// FlagOverdueTasks 标记已超过截止时间仍未提交的领取，返回本次新标记的数量。
+func (s *TaskService) FlagOverdueTasks(ctx context.Context, now time.Time) (int, error) {
+	claims, err := s.repo.MarkOverdue(ctx, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	for _, claim := range claims {
+		s.log.Info("task overdue",
+			zap.String("task_id", claim.TaskID.String()),
+			zap.String("assignee_id", claim.UserID.String()),
+			zap.Time("deadline", claim.Deadline),
+		)
+	}
+	return len(claims), nil
+}
+
+// ReleaseStaleClaims 自动释放超过截止时间与宽限期之和的领取，单个任务失败不影响其余任务。
+func (s *TaskService) ReleaseStaleClaims(ctx context.Context, now time.Time, grace time.Duration) (int, error) {
+	if grace < 0 {
+		grace = 0
+	}
+	claims, err := s.repo.ListOverdueClaims(ctx, now.UTC().Add(-grace), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, claim := range claims {
+		if ctx.Err() != nil {
+			return released, ctx.Err()
+		}
+		_, err := s.repo.Release(ctx, repository.TaskAssignmentInput{
+			TaskID: claim.TaskID,
+			UserID: claim.UserID,
+			Note:   "deadline_exceeded",
+			System: true,
+		})
+		if err != nil {
+			// 执行人可能在巡检期间刚好提交或释放，记录后继续处理其他任务。
+			s.log.Warn("auto release failed",
+				zap.String("task_id", claim.TaskID.String()),
+				zap.String("assignee_id", claim.UserID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		released++
+		s.log.Info("task auto released",
+			zap.String("task_id", claim.TaskID.String()),
+			zap.String("assignee_id", claim.UserID.String()),
+			zap.Time("deadline", claim.Deadline),
+		)
+	}
+	return released, nil
+}
+
 var tagCleaner = regexp.MustCompile(`<[^>]*>`)
 
 func (s *TaskService) extractPlainText(htmlSource string) string {
@@ -354,6 +590,25 @@ func (s *TaskService) normalizeTags(tags []string) []string {
 	return cleaned
 }
 
+func normalizeLinks(links []string) ([]string, error) {
+	if len(links) > 10 {
+		return nil, fmt.Errorf("%w: too many links", ErrValidation)
+	}
+	cleaned := make([]string, 0, len(links))
+	for _, link := range links {
+		link = strings.TrimSpace(link)
+		if link == "" {
+			continue
+		}
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: invalid link %q", ErrValidation, link)
+		}
+		cleaned = append(cleaned, link)
+	}
+	return cleaned, nil
+}
+
 func canModerateTask(tk task.Task, actorID uuid.UUID, roles []string) bool {
 	if isTaskOwner(tk, actorID) || hasAdminRole(roles) {
 		return true
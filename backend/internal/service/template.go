@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"backend/internal/domain/task"
+	"backend/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TemplateService 管理任务模板并负责按模板生成任务。
+type TemplateService struct {
+	repo  repository.TemplateRepository
+	tasks *TaskService
+	log   *zap.Logger
+}
+
+// TemplateInput 描述创建模板所需字段。
+type TemplateInput struct {
+	Title           string
+	DescriptionHTML string
+	Bounty          int64
+	Priority        task.Priority
+	Tags            []string
+	DeadlineOffset  time.Duration
+	CreatedBy       uuid.UUID
+}
+
+// TemplateUpdateInput 描述模板可更新字段，nil 表示保持不变。
+type TemplateUpdateInput struct {
+	ID              uuid.UUID
+	ActorID         uuid.UUID
+	Title           *string
+	DescriptionHTML *string
+	Bounty          *int64
+	Priority        *task.Priority
+	Tags            *[]string
+	DeadlineOffset  *time.Duration
+}
+
+// TemplateInstantiateInput 描述按模板生成任务时的覆盖字段，nil 表示沿用模板。
+type TemplateInstantiateInput struct {
+	TemplateID      uuid.UUID
+	ActorID         uuid.UUID
+	Title           *string
+	DescriptionHTML *string
+	Bounty          *int64
+	Priority        *task.Priority
+	Tags            *[]string
+	Deadline        *time.Time
+	Publish         bool
+}
+
+// NewTemplateService 构造任务模板服务。
+func NewTemplateService(repo repository.TemplateRepository, tasks *TaskService, log *zap.Logger) *TemplateService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &TemplateService{repo: repo, tasks: tasks, log: log}
+}
+
+// ListTemplates 返回全部模板。
+func (s *TemplateService) ListTemplates(ctx context.Context) ([]task.Template, error) {
+	return s.repo.List(ctx)
+}
+
+// GetTemplate 返回模板详情。
+func (s *TemplateService) GetTemplate(ctx context.Context, id uuid.UUID) (task.Template, error) {
+	tpl, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Template{}, ErrNotFound
+	}
+	return tpl, err
+}
+
+// CreateTemplate 新建模板。
+func (s *TemplateService) CreateTemplate(ctx context.Context, input TemplateInput) (task.Template, error) {
+	tpl := task.Template{
+		Title:           strings.TrimSpace(input.Title),
+		DescriptionHTML: input.DescriptionHTML,
+		Bounty:          input.Bounty,
+		Priority:        input.Priority,
+		Tags:            s.tasks.normalizeTags(input.Tags),
+		DeadlineOffset:  input.DeadlineOffset,
+		CreatedBy:       input.CreatedBy,
+	}
+	if tpl.Priority == "" {
+		tpl.Priority = task.PriorityMedium
+	}
+	if err := s.validate(tpl); err != nil {
+		return task.Template{}, err
+	}
+	return s.repo.Create(ctx, tpl)
+}
+
+// UpdateTemplate 更新模板字段。
+func (s *TemplateService) UpdateTemplate(ctx context.Context, input TemplateUpdateInput) (task.Template, error) {
+	tpl, err := s.GetTemplate(ctx, input.ID)
+	if err != nil {
+		return task.Template{}, err
+	}
+
+	if input.Title != nil {
+		tpl.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.DescriptionHTML != nil {
+		tpl.DescriptionHTML = *input.DescriptionHTML
+	}
+	if input.Bounty != nil {
+		tpl.Bounty = *input.Bounty
+	}
+	if input.Priority != nil {
+		tpl.Priority = *input.Priority
+	}
+	if input.Tags != nil {
+		tpl.Tags = s.tasks.normalizeTags(*input.Tags)
+	}
+	if input.DeadlineOffset != nil {
+		tpl.DeadlineOffset = *input.DeadlineOffset
+	}
+	if err := s.validate(tpl); err != nil {
+		return task.Template{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, tpl, input.ActorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return task.Template{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// DeleteTemplate 删除模板，已生成的任务不受影响。
+func (s *TemplateService) DeleteTemplate(ctx context.Context, id, actor uuid.UUID) error {
+	err := s.repo.Delete(ctx, id, actor)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// InstantiateTemplate 按模板生成任务，未覆盖的截止时间按模板偏移量从当前时间推算。
+func (s *TemplateService) InstantiateTemplate(ctx context.Context, input TemplateInstantiateInput) (task.Task, error) {
+	tpl, err := s.GetTemplate(ctx, input.TemplateID)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	create := TaskCreateInput{
+		Title:           tpl.Title,
+		DescriptionHTML: tpl.DescriptionHTML,
+		Bounty:          tpl.Bounty,
+		Priority:        tpl.Priority,
+		Tags:            tpl.Tags,
+		CreatedBy:       input.ActorID,
+		Publish:         input.Publish,
+	}
+	if input.Title != nil {
+		create.Title = *input.Title
+	}
+	if input.DescriptionHTML != nil {
+		create.DescriptionHTML = *input.DescriptionHTML
+	}
+	if input.Bounty != nil {
+		create.Bounty = *input.Bounty
+	}
+	if input.Priority != nil {
+		create.Priority = *input.Priority
+	}
+	if input.Tags != nil {
+		create.Tags = *input.Tags
+	}
+	switch {
+	case input.Deadline != nil:
+		create.Deadline = input.Deadline
+	case tpl.DeadlineOffset > 0:
+		deadline := time.Now().UTC().Add(tpl.DeadlineOffset)
+		create.Deadline = &deadline
+	}
+
+	return s.tasks.CreateTask(ctx, create)
+}
+
+func (s *TemplateService) validate(tpl task.Template) error {
+	return s.tasks.validatePayload(tpl.Title, tpl.DescriptionHTML, tpl.Bounty, tpl.Priority, tpl.DeadlineOffset)
+}
+
+// validatePayload 校验模板与周期计划中保存的任务字段，规则与 CreateTask 保持一致。
+func (s *TaskService) validatePayload(title, descriptionHTML string, bounty int64, priority task.Priority, deadlineOffset time.Duration) error {
+	if title == "" {
+		return fmt.Errorf("%w: title required", ErrValidation)
+	}
+	if utf8.RuneCountInString(title) > 120 {
+		return fmt.Errorf("%w: title too long", ErrValidation)
+	}
+	if strings.TrimSpace(s.extractPlainText(descriptionHTML)) == "" {
+		return fmt.Errorf("%w: description required", ErrValidation)
+	}
+	if bounty < 0 {
+		return fmt.Errorf("%w: bounty must be non-negative", ErrValidation)
+	}
+	switch priority {
+	case task.PriorityCritical, task.PriorityHigh, task.PriorityMedium, task.PriorityLow:
+	default:
+		return fmt.Errorf("%w: invalid priority", ErrValidation)
+	}
+	if deadlineOffset < 0 {
+		return fmt.Errorf("%w: deadline offset must be non-negative", ErrValidation)
+	}
+	if deadlineOffset%time.Minute != 0 {
+		return fmt.Errorf("%w: deadline offset must be whole minutes", ErrValidation)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	netmail "net/mail"
 	"strings"
 	"unicode/utf8"
 
@@ -38,6 +39,12 @@ type ProfileUpdateInput struct {
 	Bio         string
 }
 
+// EmailSettingsInput 描述通知邮箱设置，Email 为空表示清空邮箱、不再接收邮件。
+type EmailSettingsInput struct {
+	Email string
+	Mode  user.EmailMode
+}
+
 // PasswordChangeInput 描述密码更新请求。
 type PasswordChangeInput struct {
 	Current string
@@ -101,6 +108,36 @@ func (s *UserService) UpdateProfile(ctx context.Context, id uuid.UUID, input Pro
 	return usr, nil
 }
 
+// UpdateEmailSettings 更新通知邮箱与发送方式（off、instant、daily）。
+func (s *UserService) UpdateEmailSettings(ctx context.Context, id uuid.UUID, input EmailSettingsInput) (user.User, error) {
+	switch input.Mode {
+	case user.EmailModeOff, user.EmailModeInstant, user.EmailModeDaily:
+	default:
+		return user.User{}, fmt.Errorf("%w: invalid email mode", ErrValidation)
+	}
+
+	var email *string
+	if trimmed := strings.TrimSpace(input.Email); trimmed != "" {
+		addr, err := netmail.ParseAddress(trimmed)
+		if err != nil || addr.Name != "" || len(addr.Address) > 254 {
+			return user.User{}, fmt.Errorf("%w: invalid email", ErrValidation)
+		}
+		email = &addr.Address
+	}
+
+	usr, err := s.repo.UpdateEmailSettings(ctx, id, email, input.Mode)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return user.User{}, ErrNotFound
+		}
+		if err == repository.ErrConflict {
+			return user.User{}, fmt.Errorf("%w: email already in use", ErrValidation)
+		}
+		return user.User{}, err
+	}
+	return usr, nil
+}
+
 // ChangePassword 更新当前用户的密码。
 func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, input PasswordChangeInput) error {
 	if strings.TrimSpace(input.Current) == "" || strings.TrimSpace(input.New) == "" {
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/domain/outbox"
+	"backend/internal/domain/webhook"
+	"backend/internal/events"
+	"backend/internal/repository"
+)
+
+const (
+	webhookBatchSize      = 10
+	maxWebhookURLLen      = 2048
+	maxWebhookDescLen     = 200
+	minWebhookSecretLen   = 16
+	maxWebhookResponseLog = 1024
+)
+
+// webhookEventTypes 为允许订阅的事件类型。
+var webhookEventTypes = map[string]struct{}{
+	events.TypeTaskCreated:   {},
+	events.TypeTaskUpdated:   {},
+	events.TypeTaskPublished: {},
+	events.TypeTaskClaimed:   {},
+	events.TypeTaskSubmitted: {},
+	events.TypeTaskRejected:  {},
+	events.TypeTaskCompleted: {},
+}
+
+// WebhookService 管理外部 webhook 并负责投递任务事件。
+// 发件箱分发事件时只写入待投递记录，由后台巡检签名发送并按指数退避重试，业务操作不等待外部系统响应。
+type WebhookService struct {
+	cfg    config.WebhookConfig
+	repo   repository.WebhookRepository
+	client *http.Client
+	log    *zap.Logger
+}
+
+// WebhookInput 描述创建 webhook 所需字段，Secret 为空时自动生成。
+type WebhookInput struct {
+	URL         string
+	Secret      string
+	Events      []string
+	Description string
+	Active      bool
+	CreatedBy   uuid.UUID
+}
+
+// WebhookUpdateInput 描述 webhook 可更新字段，nil 表示保持不变；RotateSecret 为 true 时重新生成密钥。
+type WebhookUpdateInput struct {
+	ID           uuid.UUID
+	ActorID      uuid.UUID
+	URL          *string
+	Secret       *string
+	RotateSecret bool
+	Events       *[]string
+	Description  *string
+	Active       *bool
+}
+
+// WebhookDeliveryListResult 封装投递记录分页结果。
+type WebhookDeliveryListResult struct {
+	Items    []webhook.Delivery
+	Total    int
+	Page     int
+	PageSize int
+}
+
+// webhookPayload 为发送给接收方的请求体，重新投递时保持 ID 不变，接收方可据此去重。
+type webhookPayload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewWebhookService 构造 webhook 服务。
+func NewWebhookService(cfg config.WebhookConfig, repo repository.WebhookRepository, log *zap.Logger) *WebhookService {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &WebhookService{
+		cfg:    cfg,
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		log:    log,
+	}
+}
+
+// ListWebhooks 返回全部 webhook。
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]webhook.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+// GetWebhook 返回 webhook 详情。
+func (s *WebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (webhook.Webhook, error) {
+	hook, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return webhook.Webhook{}, ErrNotFound
+	}
+	return hook, err
+}
+
+// CreateWebhook 新建 webhook。
+func (s *WebhookService) CreateWebhook(ctx context.Context, input WebhookInput) (webhook.Webhook, error) {
+	hook := webhook.Webhook{
+		URL:         strings.TrimSpace(input.URL),
+		Secret:      strings.TrimSpace(input.Secret),
+		Description: strings.TrimSpace(input.Description),
+		Active:      input.Active,
+		CreatedBy:   input.CreatedBy,
+	}
+	if hook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return webhook.Webhook{}, err
+		}
+		hook.Secret = secret
+	}
+	subscribed, err := normalizeWebhookEvents(input.Events)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	hook.Events = subscribed
+	if err := validateWebhook(hook); err != nil {
+		return webhook.Webhook{}, err
+	}
+	return s.repo.Create(ctx, hook)
+}
+
+// UpdateWebhook 更新 webhook 字段。
+func (s *WebhookService) UpdateWebhook(ctx context.Context, input WebhookUpdateInput) (webhook.Webhook, error) {
+	hook, err := s.GetWebhook(ctx, input.ID)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if input.URL != nil {
+		hook.URL = strings.TrimSpace(*input.URL)
+	}
+	if input.Secret != nil {
+		hook.Secret = strings.TrimSpace(*input.Secret)
+	}
+	if input.RotateSecret {
+		if hook.Secret, err = generateWebhookSecret(); err != nil {
+			return webhook.Webhook{}, err
+		}
+	}
+	if input.Events != nil {
+		if hook.Events, err = normalizeWebhookEvents(*input.Events); err != nil {
+			return webhook.Webhook{}, err
+		}
+	}
+	if input.Description != nil {
+		hook.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Active != nil {
+		hook.Active = *input.Active
+	}
+	if err := validateWebhook(hook); err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, hook, input.ActorID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return webhook.Webhook{}, ErrNotFound
+	}
+	return updated, err
+}
+
+// DeleteWebhook 删除 webhook 及其投递记录。
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id, actor uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id, actor); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListDeliveries 分页返回 webhook 的投递记录，status 为空表示不筛选。
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status webhook.DeliveryStatus, page, pageSize int) (WebhookDeliveryListResult, error) {
+	switch status {
+	case "", webhook.DeliveryPending, webhook.DeliverySucceeded, webhook.DeliveryFailed:
+	default:
+		return WebhookDeliveryListResult{}, fmt.Errorf("%w: unknown delivery status", ErrValidation)
+	}
+	if _, err := s.GetWebhook(ctx, webhookID); err != nil {
+		return WebhookDeliveryListResult{}, err
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	items, total, err := s.repo.ListDeliveries(ctx, webhookID, status, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return WebhookDeliveryListResult{}, err
+	}
+	return WebhookDeliveryListResult{Items: items, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// GetDelivery 返回投递详情及每次尝试的结果。
+func (s *WebhookService) GetDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID int64) (webhook.Delivery, error) {
+	item, err := s.repo.GetDelivery(ctx, webhookID, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return webhook.Delivery{}, ErrNotFound
+	}
+	return item, err
+}
+
+// Redeliver 以相同的事件内容重新投递，生成一条新的投递记录并尽快发送。
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID uuid.UUID, deliveryID int64, actor uuid.UUID) (webhook.Delivery, error) {
+	item, err := s.repo.Redeliver(ctx, webhookID, deliveryID, actor)
+	if errors.Is(err, repository.ErrNotFound) {
+		return webhook.Delivery{}, ErrNotFound
+	}
+	return item, err
+}
+
+// Enqueue 为订阅了该事件的 webhook 创建待投递记录，作为发件箱的分发目标调用。
+// 投递记录以发件箱事件 ID 去重，发件箱重试时不会重复投递。
+func (s *WebhookService) Enqueue(ctx context.Context, msg outbox.Message) error {
+	eventID := strconv.FormatInt(msg.ID, 10)
+	body, err := json.Marshal(webhookPayload{
+		ID:        eventID,
+		Type:      msg.Topic,
+		CreatedAt: msg.CreatedAt.UTC().Format(time.RFC3339),
+		Data:      msg.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Enqueue(ctx, eventID, msg.Topic, body, time.Now().UTC())
+	return err
+}
+
+// DeliverDue 发送到期的投递并记录结果，返回本轮处理的数量。
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	// 租约需覆盖一次请求的超时时间，避免其他副本在请求进行中重复领取。
+	due, err := s.repo.ClaimDueDeliveries(ctx, now, now.Add(s.cfg.Timeout+time.Minute), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, item)
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// deliver 发送一次请求并记录结果；2xx 视为成功，其余响应与网络错误按退避间隔重试。
+func (s *WebhookService) deliver(ctx context.Context, item repository.DueDelivery) {
+	started := time.Now()
+	statusCode, respBody, sendErr := s.send(ctx, item.URL, item.Secret, item.Delivery)
+	if ctx.Err() != nil {
+		// 进程正在退出，租约到期后会重新投递，本次不计入尝试次数。
+		return
+	}
+
+	result := repository.WebhookAttemptInput{
+		DeliveryID:   item.Delivery.ID,
+		StatusCode:   statusCode,
+		ResponseBody: respBody,
+		Duration:     time.Since(started),
+		Status:       webhook.DeliverySucceeded,
+		At:           time.Now().UTC(),
+	}
+	switch {
+	case sendErr != nil:
+		result.Error = sendErr.Error()
+	case statusCode < 200 || statusCode >= 300:
+		result.Error = fmt.Sprintf("unexpected status %d", statusCode)
+	}
+	if result.Error != "" {
+		attempt := item.Delivery.Attempts + 1
+		if attempt >= s.cfg.MaxAttempts {
+			result.Status = webhook.DeliveryFailed
+		} else {
+			result.Status = webhook.DeliveryPending
+			next := result.At.Add(s.retryDelay(attempt))
+			result.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.repo.RecordAttempt(ctx, result); err != nil {
+		s.log.Error("record webhook attempt failed", zap.Int64("delivery_id", item.Delivery.ID), zap.Error(err))
+		return
+	}
+	if result.Status == webhook.DeliveryFailed {
+		s.log.Warn("webhook delivery failed permanently",
+			zap.Int64("delivery_id", item.Delivery.ID),
+			zap.String("webhook_id", item.Delivery.WebhookID.String()),
+			zap.String("error", result.Error),
+		)
+	}
+}
+
+// send 签名并发送请求，返回状态码与截断后的响应体。
+func (s *WebhookService) send(ctx context.Context, target, secret string, item webhook.Delivery) (int, string, error) {
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(item.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OpsBoard-Webhook/1.0")
+	req.Header.Set(webhook.HeaderEvent, item.EventType)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(item.ID, 10))
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(secret, timestamp, item.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseLog))
+	// 读完剩余内容以便复用连接。
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, strings.ToValidUTF8(string(body), ""), nil
+}
+
+// retryDelay 返回第 attempt 次失败后的等待时间：RetryBase * 2^(attempt-1)，不超过 RetryMax。
+func (s *WebhookService) retryDelay(attempt int) time.Duration {
+	delay := s.cfg.RetryBase
+	for i := 1; i < attempt && delay < s.cfg.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.RetryMax)
+}
+
+func normalizeWebhookEvents(input []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(input))
+	out := make([]string, 0, len(input))
+	for _, raw := range input {
+		evt := strings.TrimSpace(raw)
+		if evt == "" {
+			continue
+		}
+		if _, ok := webhookEventTypes[evt]; !ok {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrValidation, evt)
+		}
+		if _, ok := seen[evt]; ok {
+			continue
+		}
+		seen[evt] = struct{}{}
+		out = append(out, evt)
+	}
+	return out, nil
+}
+
+func validateWebhook(hook webhook.Webhook) error {
+	if hook.URL == "" {
+		return fmt.Errorf("%w: url required", ErrValidation)
+	}
+	if len(hook.URL) > maxWebhookURLLen {
+		return fmt.Errorf("%w: url too long", ErrValidation)
+	}
+	parsed, err := url.Parse(hook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) address", ErrValidation)
+	}
+	if len(hook.Secret) < minWebhookSecretLen {
+		return fmt.Errorf("%w: secret must be at least %d characters", ErrValidation, minWebhookSecretLen)
+	}
+	if utf8.RuneCountInString(hook.Description) > maxWebhookDescLen {
+		return fmt.Errorf("%w: description too long", ErrValidation)
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"backend/internal/config"
+	"backend/internal/domain/webhook"
+	"backend/internal/repository"
+)
+
+// fakeWebhookRepo 只实现投递巡检用到的方法，其余方法调用时会因内嵌接口为 nil 而 panic。
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+
+	mu       sync.Mutex
+	due      []repository.DueDelivery
+	attempts []repository.WebhookAttemptInput
+}
+
+func (f *fakeWebhookRepo) ClaimDueDeliveries(_ context.Context, _, _ time.Time, _ int) ([]repository.DueDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeWebhookRepo) RecordAttempt(_ context.Context, input repository.WebhookAttemptInput) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts = append(f.attempts, input)
+	return nil
+}
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookTestServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ok":false}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func testWebhookConfig() config.WebhookConfig {
+	return config.WebhookConfig{
+		Enabled:     true,
+		Timeout:     5 * time.Second,
+		MaxAttempts: 3,
+		RetryBase:   30 * time.Second,
+		RetryMax:    5 * time.Minute,
+	}
+}
+
+func testDueDelivery(url string, attempts int) repository.DueDelivery {
+	payload, _ := json.Marshal(webhookPayload{ID: "42", Type: "task.created", CreatedAt: "2024-01-01T00:00:00Z", Data: json.RawMessage(`{"title":"任务"}`)})
+	return repository.DueDelivery{
+		Delivery: webhook.Delivery{
+			ID:        7,
+			WebhookID: uuid.New(),
+			EventID:   "42",
+			EventType: "task.created",
+			Payload:   payload,
+			Status:    webhook.DeliveryPending,
+			Attempts:  attempts,
+		},
+		URL:    url,
+		Secret: "whsec_test_secret_value",
+	}
+}
+
+func TestWebhookDeliverSignsBody(t *testing.T) {
+	srv, requests := newWebhookTestServer(t, http.StatusNoContent)
+	item := testDueDelivery(srv.URL, 0)
+	repo := &fakeWebhookRepo{due: []repository.DueDelivery{item}}
+	svc := NewWebhookService(testWebhookConfig(), repo, nil)
+
+	n, err := svc.DeliverDue(context.Background(), time.Now())
+	if err != nil || n != 1 {
+		t.Fatalf("DeliverDue = %d, %v; want 1, nil", n, err)
+	}
+
+	req := <-requests
+	if string(req.body) != string(item.Delivery.Payload) {
+		t.Fatalf("body = %s, want %s", req.body, item.Delivery.Payload)
+	}
+	ts, err := strconv.ParseInt(req.header.Get(webhook.HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header %q", req.header.Get(webhook.HeaderTimestamp))
+	}
+	sig := req.header.Get(webhook.HeaderSignature)
+	if !webhook.Verify(item.Secret, ts, req.body, sig) {
+		t.Fatalf("signature %q does not match body", sig)
+	}
+	if webhook.Verify("other-secret-value", ts, req.body, sig) {
+		t.Fatal("signature verified with the wrong secret")
+	}
+	if got := req.header.Get(webhook.HeaderEvent); got != "task.created" {
+		t.Fatalf("event header = %q", got)
+	}
+	if got := req.header.Get(webhook.HeaderDelivery); got != "7" {
+		t.Fatalf("delivery header = %q", got)
+	}
+
+	if len(repo.attempts) != 1 {
+		t.Fatalf("recorded %d attempts, want 1", len(repo.attempts))
+	}
+	got := repo.attempts[0]
+	if got.Status != webhook.DeliverySucceeded || got.Error != "" || got.NextAttemptAt != nil {
+		t.Fatalf("attempt = %+v, want succeeded without retry", got)
+	}
+	if got.StatusCode != http.StatusNoContent {
+		t.Fatalf("status code = %d", got.StatusCode)
+	}
+}
+
+func TestWebhookDeliverRetriesWithBackoff(t *testing.T) {
+	cases := []struct {
+		name      string
+		attempts  int
+		wantDelay time.Duration
+	}{
+		{"first failure", 0, 30 * time.Second},
+		{"second failure", 1, time.Minute},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, requests := newWebhookTestServer(t, http.StatusInternalServerError)
+			repo := &fakeWebhookRepo{due: []repository.DueDelivery{testDueDelivery(srv.URL, tc.attempts)}}
+			svc := NewWebhookService(testWebhookConfig(), repo, nil)
+
+			if _, err := svc.DeliverDue(context.Background(), time.Now()); err != nil {
+				t.Fatalf("DeliverDue: %v", err)
+			}
+			<-requests
+
+			if len(repo.attempts) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(repo.attempts))
+			}
+			got := repo.attempts[0]
+			if got.Status != webhook.DeliveryPending {
+				t.Fatalf("status = %s, want pending", got.Status)
+			}
+			if got.StatusCode != http.StatusInternalServerError || got.Error == "" {
+				t.Fatalf("attempt = %+v, want recorded 500 error", got)
+			}
+			if got.ResponseBody != `{"ok":false}` {
+				t.Fatalf("response body = %q", got.ResponseBody)
+			}
+			if got.NextAttemptAt == nil {
+				t.Fatal("next attempt not scheduled")
+			}
+			if delay := got.NextAttemptAt.Sub(got.At); delay != tc.wantDelay {
+				t.Fatalf("retry delay = %s, want %s", delay, tc.wantDelay)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliverFailsAfterMaxAttempts(t *testing.T) {
+	srv, requests := newWebhookTestServer(t, http.StatusBadGateway)
+	repo := &fakeWebhookRepo{due: []repository.DueDelivery{testDueDelivery(srv.URL, 2)}}
+	svc := NewWebhookService(testWebhookConfig(), repo, nil)
+
+	if _, err := svc.DeliverDue(context.Background(), time.Now()); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	<-requests
+
+	got := repo.attempts[0]
+	if got.Status != webhook.DeliveryFailed || got.NextAttemptAt != nil {
+		t.Fatalf("attempt = %+v, want failed without retry", got)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	svc := NewWebhookService(config.WebhookConfig{RetryBase: 30 * time.Second, RetryMax: 5 * time.Minute}, nil, nil)
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, w := range want {
+		if got := svc.retryDelay(i + 1); got != w {
+			t.Errorf("retryDelay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
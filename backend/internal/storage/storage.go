@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"backend/internal/config"
+)
+
+// ErrNotFound 表示对象不存在。
+var ErrNotFound = errors.New("storage: blob not found")
+
+// BlobStore 抽象附件等二进制对象的存储后端，key 由调用方生成，使用 / 分隔的相对路径。
+type BlobStore interface {
+	// Put 写入对象，size 为 -1 表示长度未知；同名对象会被覆盖。
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open 读取对象，不存在时返回 ErrNotFound。
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Exists 判断对象是否存在。
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete 删除对象，对象不存在时不报错。
+	Delete(ctx context.Context, key string) error
+}
+
+// New 按配置选择存储后端。
+func New(ctx context.Context, cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case config.StorageDriverLocal:
+		return NewLocalStore(cfg.LocalDir)
+	case config.StorageDriverS3:
+		return NewS3Store(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// validKey 拒绝空 key、绝对路径以及包含 .. 的 key，防止越出存储根目录。
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
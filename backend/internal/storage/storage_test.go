@@ -0,0 +1,32 @@
+package storage
+
+import "testing"
+
+func TestValidKey(t *testing.T) {
+	cases := []struct {
+		key string
+		ok  bool
+	}{
+		{"attachments/2024/01/abc.png", true},
+		{"abc", true},
+		{"a/b..c/d", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"../secret", false},
+		{"a/../../secret", false},
+		{"a/./b", false},
+		{"a//b", false},
+		{"a/b/", false},
+		{`a\b`, false},
+		{`..\secret`, false},
+	}
+	for _, tc := range cases {
+		err := validKey(tc.key)
+		if tc.ok && err != nil {
+			t.Errorf("validKey(%q) = %v, want nil", tc.key, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("validKey(%q) = nil, want error", tc.key)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package transporthttp
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/service"
+
+	"go.uber.org/zap"
+)
+
+// multipartOverhead 为 multipart 边界与头部预留的额外字节数。
+const multipartOverhead = 1 << 20
+
+func (h *Handler) handleListAttachments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	attachments, err := h.services.Attachments.ListAttachments(r.Context(), taskID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]attachmentDTO, 0, len(attachments))
+	for _, att := range attachments {
+		items = append(items, mapAttachment(att))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// handleUploadAttachment 接收 multipart/form-data 中名为 file 的文件字段，可通过 submissionId 查询参数挂到某轮提交。
+func (h *Handler) handleUploadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var submissionID *int64
+	if raw := strings.TrimSpace(r.URL.Query().Get("submissionId")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_submission", "提交 ID 不合法")
+			return
+		}
+		submissionID = &id
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.services.Attachments.MaxUploadBytes()+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请使用 multipart/form-data 上传文件")
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			respondError(w, http.StatusBadRequest, "invalid_payload", "缺少 file 字段")
+			return
+		}
+		if err != nil {
+			h.respondUploadError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		created, err := h.services.Attachments.UploadAttachment(r.Context(), service.AttachmentUploadInput{
+			TaskID:        taskID,
+			SubmissionID:  submissionID,
+			UploaderID:    userID,
+			UploaderRoles: CurrentUserRoles(r.Context()),
+			Filename:      part.FileName(),
+			Content:       part,
+		})
+		part.Close()
+		if err != nil {
+			h.respondUploadError(w, err)
+			return
+		}
+
+		respondJSON(w, http.StatusCreated, mapAttachment(created))
+		return
+	}
+}
+
+func (h *Handler) handleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	attachmentID, err := parseUUIDParam(r, "attachmentId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "附件 ID 不合法")
+		return
+	}
+
+	att, body, err := h.services.Attachments.OpenAttachment(r.Context(), taskID, attachmentID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(att.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", att.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(att.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": att.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Cache-Control", "private, max-age=0, must-revalidate")
+	w.Header().Set("ETag", `"`+att.SHA256+`"`)
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, att.SHA256) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		h.log.Warn("attachment download interrupted", zap.String("attachment_id", attachmentID.String()), zap.Error(err))
+	}
+}
+
+func (h *Handler) handleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	attachmentID, err := parseUUIDParam(r, "attachmentId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "附件 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Attachments.DeleteAttachment(r.Context(), taskID, attachmentID, userID, CurrentUserRoles(r.Context())); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondUploadError 将请求体超过 MaxBytesReader 限制的错误映射为 413，其余交给通用映射。
+func (h *Handler) respondUploadError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		respondError(w, http.StatusRequestEntityTooLarge, "payload_too_large", "文件超过大小限制")
+		return
+	}
+	h.respondServiceError(w, err)
+}
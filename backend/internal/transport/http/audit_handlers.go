@@ -0,0 +1,206 @@
+package transporthttp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain/audit"
+	"backend/internal/service"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const auditExportPath = "/api/v1/audit-logs/export"
+
+func (h *Handler) handleListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.parseAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.services.Audit.ListLogs(r.Context(), input)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]auditLogDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, mapAuditLog(item))
+	}
+
+	payload := map[string]any{"items": items}
+	if result.NextCursor > 0 {
+		payload["nextCursor"] = strconv.FormatInt(result.NextCursor, 10)
+	}
+	respondJSON(w, http.StatusOK, payload)
+}
+
+func (h *Handler) handleExportAuditLogs(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.parseAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		respondError(w, http.StatusBadRequest, "invalid_format", "导出格式仅支持 csv 或 ndjson")
+		return
+	}
+
+	// 大量日志的导出可能超过服务端写超时，仅为本请求解除，避免文件在中途被截断。
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Warn("clear write deadline failed", zap.Error(err))
+	}
+
+	flusher, _ := w.(http.Flusher)
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().Format("20060102-150405"), format)
+
+	var (
+		started bool
+		count   int
+		csvOut  *csv.Writer
+		jsonOut *json.Encoder
+	)
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+		if format == "csv" {
+			csvOut = csv.NewWriter(w)
+			_ = csvOut.Write([]string{"id", "created_at", "actor_id", "actor_name", "action", "resource", "resource_id", "ip", "user_agent", "metadata"})
+		} else {
+			jsonOut = json.NewEncoder(w)
+		}
+	}
+
+	err := h.services.Audit.ExportLogs(r.Context(), input, func(item audit.Log) error {
+		start()
+		dto := mapAuditLog(item)
+		if csvOut != nil {
+			meta, err := json.Marshal(dto.Metadata)
+			if err != nil {
+				return err
+			}
+			actorID := ""
+			if dto.ActorID != nil {
+				actorID = *dto.ActorID
+			}
+			if err := csvOut.Write([]string{
+				strconv.FormatInt(dto.ID, 10),
+				dto.CreatedAt,
+				actorID,
+				csvSafe(dto.ActorName),
+				dto.Action,
+				dto.Resource,
+				csvSafe(dto.ResourceID),
+				csvSafe(dto.IP),
+				csvSafe(dto.UserAgent),
+				csvSafe(string(meta)),
+			}); err != nil {
+				return err
+			}
+		} else if err := jsonOut.Encode(dto); err != nil {
+			return err
+		}
+
+		count++
+		if count%200 == 0 {
+			if csvOut != nil {
+				csvOut.Flush()
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil && !started {
+		h.respondServiceError(w, err)
+		return
+	}
+	if err != nil {
+		// 响应头已发送，只能记录日志并截断输出。
+		h.log.Error("audit export interrupted", zap.Error(err), zap.Int("rows", count))
+		return
+	}
+
+	start()
+	if csvOut != nil {
+		csvOut.Flush()
+	}
+}
+
+func (h *Handler) parseAuditQuery(w http.ResponseWriter, r *http.Request) (service.AuditQueryInput, bool) {
+	q := r.URL.Query()
+	input := service.AuditQueryInput{
+		Resource:   q.Get("resource"),
+		ResourceID: q.Get("resourceId"),
+		Limit:      queryInt(r, "limit", 50),
+	}
+
+	if raw := strings.TrimSpace(q.Get("actor")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_actor", "操作人参数不合法")
+			return service.AuditQueryInput{}, false
+		}
+		input.ActorID = id
+	}
+	if raw := strings.TrimSpace(q.Get("action")); raw != "" {
+		input.Actions = strings.Split(raw, ",")
+	}
+	if raw := strings.TrimSpace(q.Get("from")); raw != "" {
+		if input.From = parseTime(raw); input.From == nil {
+			respondError(w, http.StatusBadRequest, "invalid_time", "起始时间不合法")
+			return service.AuditQueryInput{}, false
+		}
+	}
+	if raw := strings.TrimSpace(q.Get("to")); raw != "" {
+		if input.To = parseTime(raw); input.To == nil {
+			respondError(w, http.StatusBadRequest, "invalid_time", "结束时间不合法")
+			return service.AuditQueryInput{}, false
+		}
+	}
+	if raw := strings.TrimSpace(q.Get("cursor")); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid_cursor", "分页游标不合法")
+			return service.AuditQueryInput{}, false
+		}
+		input.Cursor = cursor
+	}
+
+	return input, true
+}
+
+// csvSafe 为以公式字符开头的单元格加上单引号前缀，防止表格软件将用户输入当作公式执行。
+func csvSafe(val string) string {
+	if val == "" {
+		return val
+	}
+	switch val[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + val
+	}
+	return val
+}
@@ -0,0 +1,170 @@
+package transporthttp
+
+import (
+	"net/http"
+
+	"backend/internal/service"
+)
+
+type createChatChannelRequest struct {
+	Name       string   `json:"name"`
+	Provider   string   `json:"provider"`
+	WebhookURL string   `json:"webhookUrl"`
+	Secret     string   `json:"secret"`
+	Events     []string `json:"events"`
+	Tags       []string `json:"tags"`
+	Priorities []string `json:"priorities"`
+	Active     *bool    `json:"active"`
+}
+
+type updateChatChannelRequest struct {
+	Name       *string   `json:"name"`
+	Provider   *string   `json:"provider"`
+	WebhookURL *string   `json:"webhookUrl"`
+	Secret     *string   `json:"secret"`
+	Events     *[]string `json:"events"`
+	Tags       *[]string `json:"tags"`
+	Priorities *[]string `json:"priorities"`
+	Active     *bool     `json:"active"`
+}
+
+func (h *Handler) handleListChatChannels(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.services.Chat.ListChannels(r.Context())
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]chatChannelDTO, 0, len(channels))
+	for _, channel := range channels {
+		items = append(items, mapChatChannel(channel))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleGetChatChannel(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "频道 ID 不合法")
+		return
+	}
+
+	channel, err := h.services.Chat.GetChannel(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapChatChannel(channel))
+}
+
+func (h *Handler) handleCreateChatChannel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req createChatChannelRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	created, err := h.services.Chat.CreateChannel(r.Context(), service.ChatChannelInput{
+		Name:       req.Name,
+		Provider:   req.Provider,
+		WebhookURL: req.WebhookURL,
+		Secret:     req.Secret,
+		Events:     req.Events,
+		Tags:       req.Tags,
+		Priorities: req.Priorities,
+		Active:     active,
+		CreatedBy:  userID,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapChatChannel(created))
+}
+
+func (h *Handler) handleUpdateChatChannel(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "频道 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateChatChannelRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Chat.UpdateChannel(r.Context(), service.ChatChannelUpdateInput{
+		ID:         id,
+		ActorID:    actor,
+		Name:       req.Name,
+		Provider:   req.Provider,
+		WebhookURL: req.WebhookURL,
+		Secret:     req.Secret,
+		Events:     req.Events,
+		Tags:       req.Tags,
+		Priorities: req.Priorities,
+		Active:     req.Active,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapChatChannel(updated))
+}
+
+func (h *Handler) handleDeleteChatChannel(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "频道 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Chat.DeleteChannel(r.Context(), id, actor); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleTestChatChannel(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "频道 ID 不合法")
+		return
+	}
+
+	if err := h.services.Chat.TestChannel(r.Context(), id); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "测试消息已发送"})
+}
@@ -0,0 +1,153 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/internal/service"
+)
+
+type addChecklistItemRequest struct {
+	Text string `json:"text"`
+}
+
+type updateChecklistItemRequest struct {
+	Text     *string `json:"text"`
+	Position *int    `json:"position"`
+}
+
+func (h *Handler) handleAddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req addChecklistItemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Tasks.AddChecklistItem(r.Context(), taskID, req.Text, userID, CurrentUserRoles(r.Context()))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapTask(updated))
+}
+
+func (h *Handler) handleUpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	itemID, err := parseChecklistItemID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "清单条目 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateChecklistItemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Tasks.UpdateChecklistItem(r.Context(), service.ChecklistItemInput{
+		TaskID:     taskID,
+		ItemID:     itemID,
+		Text:       req.Text,
+		Position:   req.Position,
+		ActorID:    userID,
+		ActorRoles: CurrentUserRoles(r.Context()),
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
+func (h *Handler) handleDeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	itemID, err := parseChecklistItemID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "清单条目 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	updated, err := h.services.Tasks.DeleteChecklistItem(r.Context(), taskID, itemID, userID, CurrentUserRoles(r.Context()))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
+func (h *Handler) handleCheckChecklistItem(w http.ResponseWriter, r *http.Request) {
+	h.setChecklistItemDone(w, r, true)
+}
+
+func (h *Handler) handleUncheckChecklistItem(w http.ResponseWriter, r *http.Request) {
+	h.setChecklistItemDone(w, r, false)
+}
+
+func (h *Handler) setChecklistItemDone(w http.ResponseWriter, r *http.Request, done bool) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	itemID, err := parseChecklistItemID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "清单条目 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	updated, err := h.services.Tasks.SetChecklistItemDone(r.Context(), taskID, itemID, done, userID, CurrentUserRoles(r.Context()))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
+func parseChecklistItemID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "itemId"), 10, 64)
+}
@@ -0,0 +1,134 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/internal/service"
+)
+
+type commentRequest struct {
+	BodyHTML string `json:"bodyHtml"`
+}
+
+func (h *Handler) handleListComments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	comments, err := h.services.Comments.ListComments(r.Context(), taskID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]commentDTO, 0, len(comments))
+	for _, comment := range comments {
+		items = append(items, mapComment(comment))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleCreateComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req commentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	created, err := h.services.Comments.CreateComment(r.Context(), service.CommentInput{
+		TaskID:   taskID,
+		AuthorID: userID,
+		BodyHTML: req.BodyHTML,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapComment(created))
+}
+
+func (h *Handler) handleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	commentID, err := parseCommentID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "评论 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req commentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Comments.UpdateComment(r.Context(), service.CommentUpdateInput{
+		TaskID:   taskID,
+		ID:       commentID,
+		ActorID:  userID,
+		BodyHTML: req.BodyHTML,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapComment(updated))
+}
+
+func (h *Handler) handleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	commentID, err := parseCommentID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "评论 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Comments.DeleteComment(r.Context(), taskID, commentID, userID, CurrentUserRoles(r.Context())); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseCommentID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "commentId"), 10, 64)
+}
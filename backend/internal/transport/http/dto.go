@@ -1,10 +1,17 @@
 package transporthttp
 
 import (
+	"encoding/json"
 	"time"
 
+	"backend/internal/domain/audit"
+	"backend/internal/domain/chat"
+	"backend/internal/domain/leaderboard"
+	"backend/internal/domain/ledger"
+	"backend/internal/domain/notification"
 	"backend/internal/domain/task"
 	"backend/internal/domain/user"
+	"backend/internal/domain/webhook"
 
 	"github.com/google/uuid"
 )
@@ -20,21 +27,91 @@ type userDTO struct {
 	Roles       []string `json:"roles"`
 }
 
+type profileDTO struct {
+	userDTO
+	EmailMode           string `json:"emailMode"`
+	UnreadNotifications int    `json:"unreadNotifications"`
+}
+
+type emailSettingsDTO struct {
+	Email     string `json:"email"`
+	EmailMode string `json:"emailMode"`
+}
+
+type notificationDTO struct {
+	ID        int64   `json:"id"`
+	Kind      string  `json:"kind"`
+	TaskID    *string `json:"taskId,omitempty"`
+	TaskTitle string  `json:"taskTitle,omitempty"`
+	ActorID   *string `json:"actorId,omitempty"`
+	ActorName string  `json:"actorName,omitempty"`
+	Title     string  `json:"title"`
+	Body      string  `json:"body,omitempty"`
+	Read      bool    `json:"read"`
+	ReadAt    *string `json:"readAt,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+}
+
 type taskDTO struct {
-	ID               string         `json:"id"`
-	Title            string         `json:"title"`
-	DescriptionHTML  string         `json:"descriptionHtml"`
-	DescriptionPlain string         `json:"descriptionPlain"`
-	Bounty           int64          `json:"bounty"`
-	Priority         string         `json:"priority"`
-	Status           string         `json:"status"`
-	Deadline         *string        `json:"deadline,omitempty"`
-	CreatedBy        string         `json:"createdBy"`
-	PublishedBy      *string        `json:"publishedBy,omitempty"`
-	CreatedAt        string         `json:"createdAt"`
-	UpdatedAt        string         `json:"updatedAt"`
-	Tags             []string       `json:"tags"`
-	CurrentAssignee  *assignmentDTO `json:"currentAssignee,omitempty"`
+	ID               string             `json:"id"`
+	Title            string             `json:"title"`
+	DescriptionHTML  string             `json:"descriptionHtml"`
+	DescriptionPlain string             `json:"descriptionPlain"`
+	Bounty           int64              `json:"bounty"`
+	Priority         string             `json:"priority"`
+	Status           string             `json:"status"`
+	Deadline         *string            `json:"deadline,omitempty"`
+	CreatedBy        string             `json:"createdBy"`
+	PublishedBy      *string            `json:"publishedBy,omitempty"`
+	CreatedAt        string             `json:"createdAt"`
+	UpdatedAt        string             `json:"updatedAt"`
+	OverdueAt        *string            `json:"overdueAt,omitempty"`
+	ParentID         *string            `json:"parentId,omitempty"`
+	ChildCount       int                `json:"childCount"`
+	TotalBounty      int64              `json:"totalBounty"`
+	Blocked          bool               `json:"blocked"`
+	BlockedBy        []dependencyDTO    `json:"blockedBy"`
+	Blocks           []dependencyDTO    `json:"blocks"`
+	RequireChecklist bool               `json:"requireChecklist"`
+	ChecklistTotal   int                `json:"checklistTotal"`
+	ChecklistDone    int                `json:"checklistDone"`
+	Progress         int                `json:"progress"`
+	Checklist        []checklistItemDTO `json:"checklist,omitempty"`
+	Watchers         []string           `json:"watchers,omitempty"`
+	Tags             []string           `json:"tags"`
+	CurrentAssignee  *assignmentDTO     `json:"currentAssignee,omitempty"`
+	Submissions      []submissionDTO    `json:"submissions,omitempty"`
+}
+
+type dependencyDTO struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type checklistItemDTO struct {
+	ID         int64   `json:"id"`
+	Position   int     `json:"position"`
+	Text       string  `json:"text"`
+	Done       bool    `json:"done"`
+	DoneBy     *string `json:"doneBy,omitempty"`
+	DoneByName string  `json:"doneByName,omitempty"`
+	DoneAt     *string `json:"doneAt,omitempty"`
+}
+
+type submissionDTO struct {
+	ID            int64    `json:"id"`
+	Round         int      `json:"round"`
+	SubmittedBy   string   `json:"submittedBy"`
+	SubmitterName string   `json:"submitterName,omitempty"`
+	Note          string   `json:"note,omitempty"`
+	Links         []string `json:"links"`
+	SubmittedAt   string   `json:"submittedAt"`
+	Verdict       string   `json:"verdict"`
+	ReviewerID    *string  `json:"reviewerId,omitempty"`
+	ReviewerName  string   `json:"reviewerName,omitempty"`
+	ReviewComment string   `json:"reviewComment,omitempty"`
+	ReviewedAt    *string  `json:"reviewedAt,omitempty"`
 }
 
 type assignmentDTO struct {
@@ -47,6 +124,151 @@ type assignmentDTO struct {
 	ReleasedAt  *string `json:"releasedAt,omitempty"`
 }
 
+type activityDTO struct {
+	Kind         string         `json:"kind"`
+	ActorID      *string        `json:"actorId,omitempty"`
+	ActorName    string         `json:"actorName,omitempty"`
+	AssigneeName string         `json:"assigneeName,omitempty"`
+	FromStatus   string         `json:"fromStatus,omitempty"`
+	ToStatus     string         `json:"toStatus,omitempty"`
+	Details      map[string]any `json:"details,omitempty"`
+	OccurredAt   string         `json:"occurredAt"`
+}
+
+type auditLogDTO struct {
+	ID         int64          `json:"id"`
+	ActorID    *string        `json:"actorId,omitempty"`
+	ActorName  string         `json:"actorName,omitempty"`
+	Action     string         `json:"action"`
+	Resource   string         `json:"resource,omitempty"`
+	ResourceID string         `json:"resourceId,omitempty"`
+	Metadata   map[string]any `json:"metadata"`
+	IP         string         `json:"ip,omitempty"`
+	UserAgent  string         `json:"userAgent,omitempty"`
+	CreatedAt  string         `json:"createdAt"`
+}
+
+type templateDTO struct {
+	ID                    string   `json:"id"`
+	Title                 string   `json:"title"`
+	DescriptionHTML       string   `json:"descriptionHtml"`
+	Bounty                int64    `json:"bounty"`
+	Priority              string   `json:"priority"`
+	Tags                  []string `json:"tags"`
+	DeadlineOffsetMinutes int      `json:"deadlineOffsetMinutes"`
+	CreatedBy             string   `json:"createdBy"`
+	CreatedAt             string   `json:"createdAt"`
+	UpdatedAt             string   `json:"updatedAt"`
+}
+
+type scheduleDTO struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	CronExpr              string   `json:"cronExpr"`
+	Timezone              string   `json:"timezone"`
+	Title                 string   `json:"title"`
+	DescriptionHTML       string   `json:"descriptionHtml"`
+	Bounty                int64    `json:"bounty"`
+	Priority              string   `json:"priority"`
+	Tags                  []string `json:"tags"`
+	DeadlineOffsetMinutes int      `json:"deadlineOffsetMinutes"`
+	Paused                bool     `json:"paused"`
+	NextRunAt             *string  `json:"nextRunAt,omitempty"`
+	LastRunAt             *string  `json:"lastRunAt,omitempty"`
+	CreatedBy             string   `json:"createdBy"`
+	CreatedAt             string   `json:"createdAt"`
+	UpdatedAt             string   `json:"updatedAt"`
+}
+
+type webhookDTO struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	CreatedBy   string   `json:"createdBy,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+}
+
+type webhookDeliveryDTO struct {
+	ID             int64               `json:"id"`
+	WebhookID      string              `json:"webhookId"`
+	EventID        string              `json:"eventId"`
+	EventType      string              `json:"eventType"`
+	Payload        json.RawMessage     `json:"payload,omitempty"`
+	Status         string              `json:"status"`
+	Attempts       int                 `json:"attempts"`
+	NextAttemptAt  *string             `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int                `json:"lastStatusCode,omitempty"`
+	LastError      string              `json:"lastError,omitempty"`
+	RedeliveryOf   *int64              `json:"redeliveryOf,omitempty"`
+	CreatedAt      string              `json:"createdAt"`
+	DeliveredAt    *string             `json:"deliveredAt,omitempty"`
+	History        []webhookAttemptDTO `json:"history,omitempty"`
+}
+
+type chatChannelDTO struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Provider   string   `json:"provider"`
+	WebhookURL string   `json:"webhookUrl"`
+	HasSecret  bool     `json:"hasSecret"`
+	Events     []string `json:"events"`
+	Tags       []string `json:"tags"`
+	Priorities []string `json:"priorities"`
+	Active     bool     `json:"active"`
+	CreatedBy  string   `json:"createdBy,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+type webhookAttemptDTO struct {
+	Attempt      int    `json:"attempt"`
+	StatusCode   *int   `json:"statusCode,omitempty"`
+	Error        string `json:"error,omitempty"`
+	ResponseBody string `json:"responseBody,omitempty"`
+	DurationMs   int64  `json:"durationMs"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type pointEntryDTO struct {
+	ID           int64   `json:"id"`
+	Kind         string  `json:"kind"`
+	Amount       int64   `json:"amount"`
+	TaskID       *string `json:"taskId,omitempty"`
+	TaskTitle    string  `json:"taskTitle,omitempty"`
+	AssignmentID *int64  `json:"assignmentId,omitempty"`
+	AdjustmentID *int64  `json:"adjustmentId,omitempty"`
+	Note         string  `json:"note,omitempty"`
+	CreatedBy    *string `json:"createdBy,omitempty"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
+type pointAdjustmentDTO struct {
+	ID         int64   `json:"id"`
+	UserID     string  `json:"userId"`
+	OperatorID string  `json:"operatorId"`
+	Kind       string  `json:"kind"`
+	Amount     int64   `json:"amount"`
+	TaskID     *string `json:"taskId,omitempty"`
+	Reason     string  `json:"reason"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+type leaderboardEntryDTO struct {
+	Rank            int    `json:"rank"`
+	UserID          string `json:"userId"`
+	Username        string `json:"username"`
+	DisplayName     string `json:"displayName"`
+	Points          int64  `json:"points"`
+	TasksCompleted  int    `json:"tasksCompleted"`
+	LastCompletedAt string `json:"lastCompletedAt"`
+	PreviousRank    *int   `json:"previousRank,omitempty"`
+	RankChange      *int   `json:"rankChange,omitempty"`
+}
+
 func mapUser(u user.User) userDTO {
 	roles := make([]string, 0, len(u.Roles))
 	for _, role := range u.Roles {
@@ -64,6 +286,32 @@ func mapUser(u user.User) userDTO {
 	}
 }
 
+func mapNotification(n notification.Notification) notificationDTO {
+	dto := notificationDTO{
+		ID:        n.ID,
+		Kind:      string(n.Kind),
+		TaskTitle: n.TaskTitle,
+		ActorName: n.ActorName,
+		Title:     n.Title,
+		Body:      n.Body,
+		Read:      n.ReadAt != nil,
+		CreatedAt: n.CreatedAt.Format(time.RFC3339),
+	}
+	if n.TaskID != nil {
+		val := n.TaskID.String()
+		dto.TaskID = &val
+	}
+	if n.ActorID != nil {
+		val := n.ActorID.String()
+		dto.ActorID = &val
+	}
+	if n.ReadAt != nil {
+		val := n.ReadAt.Format(time.RFC3339)
+		dto.ReadAt = &val
+	}
+	return dto
+}
+
 func mapTask(t task.Task) taskDTO {
 	dto := taskDTO{
 		ID:               t.ID.String(),
@@ -76,6 +324,15 @@ func mapTask(t task.Task) taskDTO {
 		CreatedBy:        t.CreatedBy.String(),
 		CreatedAt:        t.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:        t.UpdatedAt.Format(time.RFC3339),
+		ChildCount:       t.ChildCount,
+		TotalBounty:      t.TotalBounty(),
+		Blocked:          t.Blocked(),
+		BlockedBy:        mapDependencies(t.BlockedBy),
+		Blocks:           mapDependencies(t.Blocks),
+		RequireChecklist: t.RequireChecklist,
+		ChecklistTotal:   t.ChecklistTotal,
+		ChecklistDone:    t.ChecklistDone,
+		Progress:         t.ChecklistProgress(),
 		Tags:             make([]string, 0, len(t.Tags)),
 	}
 	if t.Deadline != nil {
@@ -86,6 +343,14 @@ func mapTask(t task.Task) taskDTO {
 		val := t.PublishedBy.String()
 		dto.PublishedBy = &val
 	}
+	if t.OverdueAt != nil {
+		val := t.OverdueAt.Format(time.RFC3339)
+		dto.OverdueAt = &val
+	}
+	if t.ParentID != nil {
+		val := t.ParentID.String()
+		dto.ParentID = &val
+	}
 	for _, tagItem := range t.Tags {
 		dto.Tags = append(dto.Tags, tagItem.Name)
 	}
@@ -109,5 +374,376 @@ func mapTask(t task.Task) taskDTO {
 		}
 		dto.CurrentAssignee = &assignee
 	}
+	for _, watcher := range t.Watchers {
+		dto.Watchers = append(dto.Watchers, watcher.String())
+	}
+	for _, item := range t.Checklist {
+		dto.Checklist = append(dto.Checklist, mapChecklistItem(item))
+	}
+	for _, sub := range t.Submissions {
+		dto.Submissions = append(dto.Submissions, mapSubmission(sub))
+	}
+	return dto
+}
+
+func mapChecklistItem(item task.ChecklistItem) checklistItemDTO {
+	dto := checklistItemDTO{
+		ID:         item.ID,
+		Position:   item.Position,
+		Text:       item.Text,
+		Done:       item.Done,
+		DoneByName: item.DoneByName,
+	}
+	if item.DoneBy != nil {
+		val := item.DoneBy.String()
+		dto.DoneBy = &val
+	}
+	if item.DoneAt != nil {
+		val := item.DoneAt.Format(time.RFC3339)
+		dto.DoneAt = &val
+	}
+	return dto
+}
+
+func mapDependencies(deps []task.Dependency) []dependencyDTO {
+	items := make([]dependencyDTO, 0, len(deps))
+	for _, dep := range deps {
+		items = append(items, dependencyDTO{
+			ID:     dep.TaskID.String(),
+			Title:  dep.Title,
+			Status: string(dep.Status),
+		})
+	}
+	return items
+}
+
+func mapSubmission(s task.Submission) submissionDTO {
+	dto := submissionDTO{
+		ID:            s.ID,
+		Round:         s.Round,
+		SubmittedBy:   s.SubmittedBy.String(),
+		SubmitterName: s.SubmitterName,
+		Note:          s.Note,
+		Links:         s.Links,
+		SubmittedAt:   s.SubmittedAt.Format(time.RFC3339),
+		Verdict:       string(s.Verdict),
+		ReviewerName:  s.ReviewerName,
+		ReviewComment: s.ReviewComment,
+	}
+	if dto.Links == nil {
+		dto.Links = []string{}
+	}
+	if s.ReviewerID != nil {
+		val := s.ReviewerID.String()
+		dto.ReviewerID = &val
+	}
+	if s.ReviewedAt != nil {
+		val := s.ReviewedAt.Format(time.RFC3339)
+		dto.ReviewedAt = &val
+	}
+	return dto
+}
+
+func mapPointEntry(e ledger.Entry) pointEntryDTO {
+	dto := pointEntryDTO{
+		ID:           e.ID,
+		Kind:         string(e.Kind),
+		Amount:       e.Amount,
+		TaskTitle:    e.TaskTitle,
+		AssignmentID: e.AssignmentID,
+		AdjustmentID: e.AdjustmentID,
+		Note:         e.Note,
+		CreatedAt:    e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.TaskID != nil {
+		val := e.TaskID.String()
+		dto.TaskID = &val
+	}
+	if e.CreatedBy != nil {
+		val := e.CreatedBy.String()
+		dto.CreatedBy = &val
+	}
+	return dto
+}
+
+func mapPointAdjustment(a ledger.Adjustment) pointAdjustmentDTO {
+	dto := pointAdjustmentDTO{
+		ID:         a.ID,
+		UserID:     a.UserID.String(),
+		OperatorID: a.OperatorID.String(),
+		Kind:       string(a.Kind),
+		Amount:     a.Amount,
+		Reason:     a.Reason,
+		CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+	}
+	if a.TaskID != nil {
+		val := a.TaskID.String()
+		dto.TaskID = &val
+	}
+	return dto
+}
+
+func mapStanding(st leaderboard.Standing) leaderboardEntryDTO {
+	dto := leaderboardEntryDTO{
+		Rank:            st.Rank,
+		UserID:          st.UserID.String(),
+		Username:        st.Username,
+		DisplayName:     st.DisplayName,
+		Points:          st.Points,
+		TasksCompleted:  st.TasksCompleted,
+		LastCompletedAt: st.LastCompletedAt.Format(time.RFC3339),
+		PreviousRank:    st.PreviousRank,
+	}
+	if st.PreviousRank != nil {
+		// 正数表示名次上升。
+		change := *st.PreviousRank - st.Rank
+		dto.RankChange = &change
+	}
+	return dto
+}
+
+func mapActivity(a task.Activity) activityDTO {
+	dto := activityDTO{
+		Kind:         a.Kind,
+		ActorName:    a.ActorName,
+		AssigneeName: a.AssigneeName,
+		FromStatus:   string(a.FromStatus),
+		ToStatus:     string(a.ToStatus),
+		Details:      a.Details,
+		OccurredAt:   a.OccurredAt.Format(time.RFC3339),
+	}
+	if a.ActorID != nil {
+		val := a.ActorID.String()
+		dto.ActorID = &val
+	}
+	return dto
+}
+
+func mapAuditLog(l audit.Log) auditLogDTO {
+	dto := auditLogDTO{
+		ID:         l.ID,
+		ActorName:  l.ActorName,
+		Action:     string(l.Action),
+		Resource:   l.Resource,
+		ResourceID: l.ResourceID,
+		Metadata:   l.Metadata,
+		IP:         l.IP,
+		UserAgent:  l.UserAgent,
+		CreatedAt:  l.CreatedAt.Format(time.RFC3339),
+	}
+	if dto.Metadata == nil {
+		dto.Metadata = map[string]any{}
+	}
+	if l.ActorID != nil {
+		val := l.ActorID.String()
+		dto.ActorID = &val
+	}
+	return dto
+}
+
+type commentDTO struct {
+	ID         int64        `json:"id"`
+	TaskID     string       `json:"taskId"`
+	AuthorID   string       `json:"authorId"`
+	AuthorName string       `json:"authorName"`
+	BodyHTML   string       `json:"bodyHtml"`
+	BodyPlain  string       `json:"bodyPlain"`
+	Mentions   []mentionDTO `json:"mentions"`
+	CreatedAt  string       `json:"createdAt"`
+	UpdatedAt  string       `json:"updatedAt"`
+	EditedAt   *string      `json:"editedAt,omitempty"`
+}
+
+type mentionDTO struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+}
+
+func mapComment(c task.Comment) commentDTO {
+	dto := commentDTO{
+		ID:         c.ID,
+		TaskID:     c.TaskID.String(),
+		AuthorID:   c.AuthorID.String(),
+		AuthorName: c.AuthorName,
+		BodyHTML:   c.BodyHTML,
+		BodyPlain:  c.BodyPlain,
+		Mentions:   make([]mentionDTO, 0, len(c.Mentions)),
+		CreatedAt:  c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  c.UpdatedAt.Format(time.RFC3339),
+	}
+	if c.EditedAt != nil {
+		val := c.EditedAt.Format(time.RFC3339)
+		dto.EditedAt = &val
+	}
+	for _, mention := range c.Mentions {
+		dto.Mentions = append(dto.Mentions, mentionDTO{UserID: mention.UserID.String(), Username: mention.Username})
+	}
+	return dto
+}
+
+type attachmentDTO struct {
+	ID           string `json:"id"`
+	TaskID       string `json:"taskId"`
+	SubmissionID *int64 `json:"submissionId,omitempty"`
+	Filename     string `json:"filename"`
+	ContentType  string `json:"contentType"`
+	Size         int64  `json:"size"`
+	SHA256       string `json:"sha256"`
+	UploadedBy   string `json:"uploadedBy"`
+	UploaderName string `json:"uploaderName"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+func mapAttachment(a task.Attachment) attachmentDTO {
+	return attachmentDTO{
+		ID:           a.ID.String(),
+		TaskID:       a.TaskID.String(),
+		SubmissionID: a.SubmissionID,
+		Filename:     a.Filename,
+		ContentType:  a.ContentType,
+		Size:         a.Size,
+		SHA256:       a.SHA256,
+		UploadedBy:   a.UploadedBy.String(),
+		UploaderName: a.UploaderName,
+		CreatedAt:    a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapTemplate(t task.Template) templateDTO {
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return templateDTO{
+		ID:                    t.ID.String(),
+		Title:                 t.Title,
+		DescriptionHTML:       t.DescriptionHTML,
+		Bounty:                t.Bounty,
+		Priority:              string(t.Priority),
+		Tags:                  tags,
+		DeadlineOffsetMinutes: int(t.DeadlineOffset / time.Minute),
+		CreatedBy:             t.CreatedBy.String(),
+		CreatedAt:             t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             t.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapSchedule(s task.Schedule) scheduleDTO {
+	tags := s.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	dto := scheduleDTO{
+		ID:                    s.ID.String(),
+		Name:                  s.Name,
+		CronExpr:              s.CronExpr,
+		Timezone:              s.Timezone,
+		Title:                 s.Title,
+		DescriptionHTML:       s.DescriptionHTML,
+		Bounty:                s.Bounty,
+		Priority:              string(s.Priority),
+		Tags:                  tags,
+		DeadlineOffsetMinutes: int(s.DeadlineOffset / time.Minute),
+		Paused:                s.Paused,
+		CreatedBy:             s.CreatedBy.String(),
+		CreatedAt:             s.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             s.UpdatedAt.Format(time.RFC3339),
+	}
+	if s.NextRunAt != nil {
+		val := s.NextRunAt.Format(time.RFC3339)
+		dto.NextRunAt = &val
+	}
+	if s.LastRunAt != nil {
+		val := s.LastRunAt.Format(time.RFC3339)
+		dto.LastRunAt = &val
+	}
+	return dto
+}
+
+// mapWebhook 转换 webhook，密钥仅在创建或轮换后返回一次。
+func mapWebhook(w webhook.Webhook, revealSecret bool) webhookDTO {
+	events := w.Events
+	if events == nil {
+		events = []string{}
+	}
+	dto := webhookDTO{
+		ID:          w.ID.String(),
+		URL:         w.URL,
+		Events:      events,
+		Description: w.Description,
+		Active:      w.Active,
+		CreatedAt:   w.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   w.UpdatedAt.Format(time.RFC3339),
+	}
+	if w.CreatedBy != uuid.Nil {
+		dto.CreatedBy = w.CreatedBy.String()
+	}
+	if revealSecret {
+		dto.Secret = w.Secret
+	}
+	return dto
+}
+
+// mapWebhookDelivery 转换投递记录，列表中省略请求体以减小响应。
+func mapWebhookDelivery(d webhook.Delivery, withPayload bool) webhookDeliveryDTO {
+	dto := webhookDeliveryDTO{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID.String(),
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		RedeliveryOf:   d.RedeliveryOf,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if withPayload {
+		dto.Payload = d.Payload
+	}
+	if d.NextAttemptAt != nil && d.Status == webhook.DeliveryPending {
+		val := d.NextAttemptAt.Format(time.RFC3339)
+		dto.NextAttemptAt = &val
+	}
+	if d.DeliveredAt != nil {
+		val := d.DeliveredAt.Format(time.RFC3339)
+		dto.DeliveredAt = &val
+	}
+	for _, attempt := range d.History {
+		dto.History = append(dto.History, webhookAttemptDTO{
+			Attempt:      attempt.Attempt,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMs:   attempt.Duration.Milliseconds(),
+			CreatedAt:    attempt.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return dto
+}
+
+// mapChatChannel 转换群机器人频道，加签密钥不返回，只标明是否已设置。
+func mapChatChannel(c chat.Channel) chatChannelDTO {
+	dto := chatChannelDTO{
+		ID:         c.ID.String(),
+		Name:       c.Name,
+		Provider:   string(c.Provider),
+		WebhookURL: c.WebhookURL,
+		HasSecret:  c.Secret != "",
+		Events:     make([]string, 0, len(c.Events)),
+		Tags:       make([]string, 0, len(c.Tags)),
+		Priorities: make([]string, 0, len(c.Priorities)),
+		Active:     c.Active,
+		CreatedAt:  c.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  c.UpdatedAt.Format(time.RFC3339),
+	}
+	dto.Events = append(dto.Events, c.Events...)
+	dto.Tags = append(dto.Tags, c.Tags...)
+	for _, p := range c.Priorities {
+		dto.Priorities = append(dto.Priorities, string(p))
+	}
+	if c.CreatedBy != uuid.Nil {
+		dto.CreatedBy = c.CreatedBy.String()
+	}
 	return dto
 }
@@ -0,0 +1,78 @@
+package transporthttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/events"
+)
+
+const eventStreamPath = "/api/v1/events"
+
+// handleEvents 以 SSE 推送任务看板事件，支持 Last-Event-ID 断线续传。
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// 服务端的读写超时对长连接同样生效，仅为本连接解除，其余请求不受影响。
+	if err := rc.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Warn("clear read deadline failed", zap.Error(err))
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.log.Warn("clear write deadline failed", zap.Error(err))
+	}
+
+	lastID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if lastID == "" {
+		lastID = strings.TrimSpace(r.URL.Query().Get("lastEventId"))
+	}
+
+	sub, replay, resumed := h.events.Subscribe(lastID)
+	defer h.events.Unsubscribe(sub)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !resumed {
+		// 积压窗口内找不到 Last-Event-ID，提示客户端重新拉取完整看板。
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, evt := range replay {
+		writeEvent(w, evt)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.events.Heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			writeEvent(w, evt)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, evt events.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", evt.ID, evt.Type, evt.Data)
+}
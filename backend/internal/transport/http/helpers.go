@@ -3,10 +3,12 @@ package transporthttp
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"backend/internal/domain/task"
 	"backend/internal/repository"
 	"backend/internal/service"
 
@@ -22,6 +24,14 @@ func decodeJSON(r *http.Request, v any) error {
 	return dec.Decode(v)
 }
 
+// decodeOptionalJSON 与 decodeJSON 相同，但允许请求体为空。
+func decodeOptionalJSON(r *http.Request, v any) error {
+	if err := decodeJSON(r, v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
 func (h *Handler) respondServiceError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, service.ErrValidation):
@@ -32,6 +42,20 @@ func (h *Handler) respondServiceError(w http.ResponseWriter, err error) {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
 	case errors.Is(err, service.ErrForbidden):
 		respondError(w, http.StatusForbidden, "forbidden", "权限不足")
+	case errors.Is(err, task.ErrInvalidTransition):
+		respondError(w, http.StatusConflict, "invalid_transition", err.Error())
+	case errors.Is(err, service.ErrConflict), errors.Is(err, repository.ErrConflict):
+		respondError(w, http.StatusConflict, "conflict", "资源状态已变更，请刷新后重试")
+	case errors.Is(err, service.ErrClaimLimitReached):
+		respondError(w, http.StatusConflict, "claim_limit_reached", "同时领取的任务数已达上限")
+	case errors.Is(err, service.ErrTaskBlocked):
+		respondError(w, http.StatusConflict, "task_blocked", "前置任务尚未完成，暂不可领取")
+	case errors.Is(err, service.ErrPayloadTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, "payload_too_large", "文件超过大小限制")
+	case errors.Is(err, service.ErrClaimCooldown):
+		respondError(w, http.StatusTooManyRequests, "claim_cooldown", "刚释放的任务需等待冷却后才能再次领取")
+	case errors.Is(err, service.ErrRateLimited):
+		respondError(w, http.StatusTooManyRequests, "rate_limited", "操作过于频繁，请稍后再试")
 	case errors.Is(err, service.ErrNotFound), errors.Is(err, repository.ErrNotFound):
 		respondError(w, http.StatusNotFound, "not_found", "资源不存在")
 	default:
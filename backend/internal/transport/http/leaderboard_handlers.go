@@ -0,0 +1,43 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/leaderboard"
+	"backend/internal/service"
+)
+
+func (h *Handler) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	period := leaderboard.Period(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("period"))))
+
+	result, err := h.services.Leaderboard.GetLeaderboard(r.Context(), service.LeaderboardInput{
+		Period: period,
+		Tag:    r.URL.Query().Get("tag"),
+		Limit:  queryInt(r, "limit", 50),
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]leaderboardEntryDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, mapStanding(item))
+	}
+
+	payload := map[string]any{
+		"period": string(result.Period),
+		"tag":    result.Tag,
+		"items":  items,
+	}
+	if result.From != nil {
+		payload["from"] = result.From.Format(time.RFC3339)
+	}
+	if result.To != nil {
+		payload["to"] = result.To.Format(time.RFC3339)
+	}
+
+	respondJSON(w, http.StatusOK, payload)
+}
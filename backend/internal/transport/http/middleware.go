@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"backend/internal/domain/audit"
 	"backend/internal/domain/user"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -31,6 +32,19 @@ func (h *Handler) requestLogger() func(http.Handler) http.Handler {
 	}
 }
 
+// auditContext 将客户端 IP 与 User-Agent 写入请求 context，供审计日志使用。
+func (h *Handler) auditContext() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := audit.WithClient(r.Context(), audit.Client{
+				IP:        h.clientIP(r),
+				UserAgent: r.Header.Get("User-Agent"),
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 func (h *Handler) authRequired() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -91,3 +105,32 @@ func extractBearer(header string) string {
 func uuidFromString(val string) (uuid.UUID, error) {
 	return uuid.Parse(strings.TrimSpace(val))
 }
+
+// requestTimeout 为普通请求套用超时；SSE 长连接与审计日志导出的生命周期由各自的处理函数管理，不受此限制。
+func (h *Handler) requestTimeout(d time.Duration) func(http.Handler) http.Handler {
+	timeout := middleware.Timeout(d)
+	return func(next http.Handler) http.Handler {
+		limited := timeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == eventStreamPath || r.URL.Path == auditExportPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+// streamToken 在缺少 Authorization 头时从 access_token 查询参数读取令牌，仅用于 SSE。
+func (h *Handler) streamToken() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				if token := strings.TrimSpace(r.URL.Query().Get("access_token")); token != "" {
+					r.Header.Set("Authorization", "Bearer "+token)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+	result, err := h.services.Notifications.ListNotifications(r.Context(), userID, unreadOnly, queryInt(r, "page", 1), queryInt(r, "pageSize", 20))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]notificationDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, mapNotification(item))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"items":    items,
+		"total":    result.Total,
+		"unread":   result.Unread,
+		"page":     result.Page,
+		"pageSize": result.PageSize,
+	})
+}
+
+func (h *Handler) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "通知 ID 不合法")
+		return
+	}
+
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Notifications.MarkRead(r.Context(), userID, id); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	marked, err := h.services.Notifications.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{"marked": marked})
+}
@@ -0,0 +1,96 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strings"
+
+	"backend/internal/domain/ledger"
+	"backend/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type adjustPointsRequest struct {
+	Kind   string `json:"kind"`
+	Amount int64  `json:"amount"`
+	TaskID string `json:"taskId"`
+	Reason string `json:"reason"`
+}
+
+func (h *Handler) handleGetMyPoints(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	result, err := h.services.Ledger.GetUserPoints(r.Context(), userID, service.PointsListInput{
+		Page:     queryInt(r, "page", 1),
+		PageSize: queryInt(r, "pageSize", 20),
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	entries := make([]pointEntryDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		entries = append(entries, mapPointEntry(item))
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"balance":  result.Balance,
+		"items":    entries,
+		"total":    result.Total,
+		"page":     result.Page,
+		"pageSize": result.PageSize,
+	})
+}
+
+func (h *Handler) handleAdjustPoints(w http.ResponseWriter, r *http.Request) {
+	operatorID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	targetID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "用户 ID 不合法")
+		return
+	}
+
+	var req adjustPointsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	var taskID *uuid.UUID
+	if raw := strings.TrimSpace(req.TaskID); raw != "" {
+		parsed, parseErr := uuid.Parse(raw)
+		if parseErr != nil {
+			respondError(w, http.StatusBadRequest, "invalid_task_id", "任务 ID 不合法")
+			return
+		}
+		taskID = &parsed
+	}
+
+	result, err := h.services.Ledger.AdjustPoints(r.Context(), service.PointsAdjustInput{
+		UserID:     targetID,
+		OperatorID: operatorID,
+		Kind:       ledger.AdjustmentKind(strings.TrimSpace(req.Kind)),
+		Amount:     req.Amount,
+		TaskID:     taskID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"adjustment": mapPointAdjustment(result.Adjustment),
+		"balance":    result.Balance,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"backend/internal/config"
+	"backend/internal/events"
 	"backend/internal/service"
 
 	"github.com/go-chi/chi/v5"
@@ -18,19 +19,21 @@ import (
 type Handler struct {
 	cfg      config.Config
 	services service.Registry
+	events   *events.Broker
 	log      *zap.Logger
 }
 
 // NewRouter 构建完整的 HTTP 路由。
-func NewRouter(cfg config.Config, services service.Registry, log *zap.Logger) http.Handler {
-	h := &Handler{cfg: cfg, services: services, log: log}
+func NewRouter(cfg config.Config, services service.Registry, broker *events.Broker, log *zap.Logger) http.Handler {
+	h := &Handler{cfg: cfg, services: services, events: broker, log: log}
 
 	r := chi.NewRouter()
 	r.Use(chimiddleware.RequestID)
 	r.Use(chimiddleware.RealIP)
 	r.Use(chimiddleware.Recoverer)
-	r.Use(chimiddleware.Timeout(60 * time.Second))
+	r.Use(h.requestTimeout(60 * time.Second))
 	r.Use(h.requestLogger())
+	r.Use(h.auditContext())
 
 	corsOpts := cors.Options{
 		AllowedOrigins:   cfg.Server.AllowOrigins,
@@ -51,15 +54,44 @@ func NewRouter(cfg config.Config, services service.Registry, log *zap.Logger) ht
 		api.Post("/auth/refresh", h.handleRefresh)
 		api.Post("/auth/logout", h.handleLogout)
 
+		// EventSource 无法设置请求头，允许通过 access_token 查询参数携带令牌。
+		api.With(h.streamToken(), h.authRequired()).Get("/events", h.handleEvents)
+
 		api.Group(func(priv chi.Router) {
 			priv.Use(h.authRequired())
 
 			priv.Get("/users/me", h.handleGetProfile)
 			priv.Patch("/users/me/profile", h.handleUpdateProfile)
 			priv.Patch("/users/me/password", h.handleChangePassword)
+			priv.Patch("/users/me/email", h.handleUpdateEmailSettings)
+			priv.Post("/users/me/email/test", h.handleSendTestEmail)
+			priv.Get("/users/me/points", h.handleGetMyPoints)
+
+			priv.Get("/notifications", h.handleListNotifications)
+			priv.Post("/notifications/read-all", h.handleMarkAllNotificationsRead)
+			priv.Post("/notifications/{id}/read", h.handleMarkNotificationRead)
+
+			priv.Get("/leaderboard", h.handleGetLeaderboard)
 
 			priv.Get("/tasks", h.handleListTasks)
 			priv.Get("/tasks/{id}", h.handleGetTask)
+			priv.Get("/tasks/{id}/activity", h.handleGetTaskActivity)
+			priv.Get("/tasks/{id}/children", h.handleListChildTasks)
+			priv.Get("/tasks/{id}/comments", h.handleListComments)
+			priv.Post("/tasks/{id}/comments", h.handleCreateComment)
+			priv.Patch("/tasks/{id}/comments/{commentId}", h.handleUpdateComment)
+			priv.Delete("/tasks/{id}/comments/{commentId}", h.handleDeleteComment)
+			priv.Get("/tasks/{id}/attachments", h.handleListAttachments)
+			priv.Post("/tasks/{id}/attachments", h.handleUploadAttachment)
+			priv.Get("/tasks/{id}/attachments/{attachmentId}", h.handleDownloadAttachment)
+			priv.Delete("/tasks/{id}/attachments/{attachmentId}", h.handleDeleteAttachment)
+			priv.Post("/tasks/{id}/checklist", h.handleAddChecklistItem)
+			priv.Patch("/tasks/{id}/checklist/{itemId}", h.handleUpdateChecklistItem)
+			priv.Delete("/tasks/{id}/checklist/{itemId}", h.handleDeleteChecklistItem)
+			priv.Post("/tasks/{id}/checklist/{itemId}/check", h.handleCheckChecklistItem)
+			priv.Post("/tasks/{id}/checklist/{itemId}/uncheck", h.handleUncheckChecklistItem)
+			priv.Post("/tasks/{id}/watch", h.handleWatchTask)
+			priv.Delete("/tasks/{id}/watch", h.handleUnwatchTask)
 			priv.Post("/tasks/{id}/claim", h.handleClaimTask)
 			priv.Post("/tasks/{id}/release", h.handleReleaseTask)
 			priv.Post("/tasks/{id}/submit", h.handleSubmitTask)
@@ -69,13 +101,52 @@ func NewRouter(cfg config.Config, services service.Registry, log *zap.Logger) ht
 			priv.Group(func(admin chi.Router) {
 				admin.Use(h.adminRequired())
 				admin.Post("/tasks", h.handleCreateTask)
+				admin.Post("/tasks/{id}/children", h.handleCreateChildTask)
+				admin.Post("/tasks/{id}/dependencies", h.handleAddTaskDependency)
+				admin.Delete("/tasks/{id}/dependencies/{blockerId}", h.handleRemoveTaskDependency)
 				admin.Patch("/tasks/{id}", h.handleUpdateTask)
 				admin.Delete("/tasks/{id}", h.handleDeleteTask)
 				admin.Post("/tasks/{id}/publish", h.handlePublishTask)
 				admin.Post("/tasks/{id}/archive", h.handleArchiveTask)
 
+				admin.Get("/task-templates", h.handleListTemplates)
+				admin.Post("/task-templates", h.handleCreateTemplate)
+				admin.Get("/task-templates/{id}", h.handleGetTemplate)
+				admin.Patch("/task-templates/{id}", h.handleUpdateTemplate)
+				admin.Delete("/task-templates/{id}", h.handleDeleteTemplate)
+				admin.Post("/task-templates/{id}/instantiate", h.handleInstantiateTemplate)
+
+				admin.Get("/task-schedules", h.handleListSchedules)
+				admin.Post("/task-schedules", h.handleCreateSchedule)
+				admin.Get("/task-schedules/{id}", h.handleGetSchedule)
+				admin.Patch("/task-schedules/{id}", h.handleUpdateSchedule)
+				admin.Delete("/task-schedules/{id}", h.handleDeleteSchedule)
+				admin.Get("/task-schedules/{id}/occurrences", h.handleListScheduleOccurrences)
+				admin.Post("/task-schedules/{id}/pause", h.handlePauseSchedule)
+				admin.Post("/task-schedules/{id}/resume", h.handleResumeSchedule)
+
+				admin.Get("/webhooks", h.handleListWebhooks)
+				admin.Post("/webhooks", h.handleCreateWebhook)
+				admin.Get("/webhooks/{id}", h.handleGetWebhook)
+				admin.Patch("/webhooks/{id}", h.handleUpdateWebhook)
+				admin.Delete("/webhooks/{id}", h.handleDeleteWebhook)
+				admin.Get("/webhooks/{id}/deliveries", h.handleListWebhookDeliveries)
+				admin.Get("/webhooks/{id}/deliveries/{deliveryId}", h.handleGetWebhookDelivery)
+				admin.Post("/webhooks/{id}/deliveries/{deliveryId}/redeliver", h.handleRedeliverWebhook)
+
+				admin.Get("/chat-channels", h.handleListChatChannels)
+				admin.Post("/chat-channels", h.handleCreateChatChannel)
+				admin.Get("/chat-channels/{id}", h.handleGetChatChannel)
+				admin.Patch("/chat-channels/{id}", h.handleUpdateChatChannel)
+				admin.Delete("/chat-channels/{id}", h.handleDeleteChatChannel)
+				admin.Post("/chat-channels/{id}/test", h.handleTestChatChannel)
+
 				admin.Get("/users", h.handleListUsers)
 				admin.Post("/users/{id}/toggle-admin", h.handleToggleAdmin)
+				admin.Post("/users/{id}/points/adjust", h.handleAdjustPoints)
+
+				admin.Get("/audit-logs", h.handleListAuditLogs)
+				admin.Get("/audit-logs/export", h.handleExportAuditLogs)
 			})
 		})
 	})
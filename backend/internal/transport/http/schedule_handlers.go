@@ -0,0 +1,234 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/task"
+	"backend/internal/service"
+)
+
+type createScheduleRequest struct {
+	Name                  string   `json:"name"`
+	CronExpr              string   `json:"cronExpr"`
+	Timezone              string   `json:"timezone"`
+	Title                 string   `json:"title"`
+	DescriptionHTML       string   `json:"descriptionHtml"`
+	Bounty                int64    `json:"bounty"`
+	Priority              string   `json:"priority"`
+	Tags                  []string `json:"tags"`
+	TagsText              string   `json:"tagsText"`
+	DeadlineOffsetMinutes int      `json:"deadlineOffsetMinutes"`
+	Paused                bool     `json:"paused"`
+}
+
+type updateScheduleRequest struct {
+	Name                  *string   `json:"name"`
+	CronExpr              *string   `json:"cronExpr"`
+	Timezone              *string   `json:"timezone"`
+	Title                 *string   `json:"title"`
+	DescriptionHTML       *string   `json:"descriptionHtml"`
+	Bounty                *int64    `json:"bounty"`
+	Priority              *string   `json:"priority"`
+	Tags                  *[]string `json:"tags"`
+	TagsText              *string   `json:"tagsText"`
+	DeadlineOffsetMinutes *int      `json:"deadlineOffsetMinutes"`
+}
+
+func (h *Handler) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.services.Schedules.ListSchedules(r.Context())
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]scheduleDTO, 0, len(schedules))
+	for _, sch := range schedules {
+		items = append(items, mapSchedule(sch))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "计划 ID 不合法")
+		return
+	}
+
+	sch, err := h.services.Schedules.GetSchedule(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapSchedule(sch))
+}
+
+func (h *Handler) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req createScheduleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	created, err := h.services.Schedules.CreateSchedule(r.Context(), service.ScheduleInput{
+		Name:            req.Name,
+		CronExpr:        req.CronExpr,
+		Timezone:        req.Timezone,
+		Title:           req.Title,
+		DescriptionHTML: req.DescriptionHTML,
+		Bounty:          req.Bounty,
+		Priority:        task.Priority(strings.TrimSpace(req.Priority)),
+		Tags:            mergeTags(req.Tags, req.TagsText),
+		DeadlineOffset:  time.Duration(req.DeadlineOffsetMinutes) * time.Minute,
+		Paused:          req.Paused,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapSchedule(created))
+}
+
+func (h *Handler) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "计划 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateScheduleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	input := service.ScheduleUpdateInput{
+		ID:              id,
+		ActorID:         actor,
+		Name:            req.Name,
+		CronExpr:        req.CronExpr,
+		Timezone:        req.Timezone,
+		Title:           req.Title,
+		DescriptionHTML: req.DescriptionHTML,
+		Bounty:          req.Bounty,
+	}
+	if req.Priority != nil {
+		priority := task.Priority(strings.TrimSpace(*req.Priority))
+		input.Priority = &priority
+	}
+	if req.Tags != nil || req.TagsText != nil {
+		tags := make([]string, 0)
+		if req.Tags != nil {
+			tags = mergeTags(*req.Tags, "")
+		}
+		if req.TagsText != nil {
+			tags = mergeTags(tags, *req.TagsText)
+		}
+		input.Tags = &tags
+	}
+	if req.DeadlineOffsetMinutes != nil {
+		offset := time.Duration(*req.DeadlineOffsetMinutes) * time.Minute
+		input.DeadlineOffset = &offset
+	}
+
+	updated, err := h.services.Schedules.UpdateSchedule(r.Context(), input)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapSchedule(updated))
+}
+
+func (h *Handler) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "计划 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Schedules.DeleteSchedule(r.Context(), id, actor); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleListScheduleOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "计划 ID 不合法")
+		return
+	}
+
+	occurrences, err := h.services.Schedules.UpcomingOccurrences(r.Context(), id, queryInt(r, "count", 10))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		items = append(items, occurrence.Format(time.RFC3339))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handlePauseSchedule(w http.ResponseWriter, r *http.Request) {
+	h.setSchedulePaused(w, r, true)
+}
+
+func (h *Handler) handleResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	h.setSchedulePaused(w, r, false)
+}
+
+func (h *Handler) setSchedulePaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "计划 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var updated task.Schedule
+	if paused {
+		updated, err = h.services.Schedules.PauseSchedule(r.Context(), id, actor)
+	} else {
+		updated, err = h.services.Schedules.ResumeSchedule(r.Context(), id, actor)
+	}
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapSchedule(updated))
+}
@@ -2,6 +2,7 @@ package transporthttp
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,25 +13,41 @@ import (
 )
 
 type createTaskRequest struct {
-	Title           string   `json:"title"`
-	DescriptionHTML string   `json:"descriptionHtml"`
-	Bounty          int64    `json:"bounty"`
-	Priority        string   `json:"priority"`
-	Deadline        string   `json:"deadline"`
-	Tags            []string `json:"tags"`
-	TagsText        string   `json:"tagsText"`
-	Publish         bool     `json:"publish"`
+	Title            string   `json:"title"`
+	DescriptionHTML  string   `json:"descriptionHtml"`
+	Bounty           int64    `json:"bounty"`
+	Priority         string   `json:"priority"`
+	Deadline         string   `json:"deadline"`
+	Tags             []string `json:"tags"`
+	TagsText         string   `json:"tagsText"`
+	RequireChecklist bool     `json:"requireChecklist"`
+	Checklist        []string `json:"checklist"`
+	Publish          bool     `json:"publish"`
 }
 
 type updateTaskRequest struct {
-	Title           *string   `json:"title"`
-	DescriptionHTML *string   `json:"descriptionHtml"`
-	Bounty          *int64    `json:"bounty"`
-	Priority        *string   `json:"priority"`
-	Deadline        *string   `json:"deadline"`
-	Tags            *[]string `json:"tags"`
-	TagsText        *string   `json:"tagsText"`
-	Status          *string   `json:"status"`
+	Title            *string   `json:"title"`
+	DescriptionHTML  *string   `json:"descriptionHtml"`
+	Bounty           *int64    `json:"bounty"`
+	Priority         *string   `json:"priority"`
+	Deadline         *string   `json:"deadline"`
+	Tags             *[]string `json:"tags"`
+	TagsText         *string   `json:"tagsText"`
+	Status           *string   `json:"status"`
+	RequireChecklist *bool     `json:"requireChecklist"`
+}
+
+type submitTaskRequest struct {
+	Note  string   `json:"note"`
+	Links []string `json:"links"`
+}
+
+type reviewTaskRequest struct {
+	Comment string `json:"comment"`
+}
+
+type addDependencyRequest struct {
+	BlockedBy string `json:"blockedBy"`
 }
 
 func (h *Handler) handleListTasks(w http.ResponseWriter, r *http.Request) {
@@ -71,13 +88,49 @@ func (h *Handler) handleListTasks(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	parentID := uuid.Nil
+	if parentParam := strings.TrimSpace(r.URL.Query().Get("parent")); parentParam != "" {
+		id, parseErr := uuid.Parse(parentParam)
+		if parseErr != nil {
+			respondError(w, http.StatusBadRequest, "invalid_parent", "父任务参数不合法")
+			return
+		}
+		parentID = id
+	}
+	watchParam := strings.TrimSpace(r.URL.Query().Get("watching"))
+	watchedBy := uuid.Nil
+	if watchParam != "" {
+		if strings.EqualFold(watchParam, "me") {
+			userID, ok := CurrentUserID(r.Context())
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+				return
+			}
+			watchedBy = userID
+		} else {
+			id, parseErr := uuid.Parse(watchParam)
+			if parseErr != nil {
+				respondError(w, http.StatusBadRequest, "invalid_watching", "关注者参数不合法")
+				return
+			}
+			watchedBy = id
+		}
+	}
+
+	rootOnly, _ := strconv.ParseBool(r.URL.Query().Get("rootOnly"))
+	hideBlocked, _ := strconv.ParseBool(r.URL.Query().Get("hideBlocked"))
+
 	result, err := h.services.Tasks.ListTasks(r.Context(), service.TaskListInput{
-		Keyword:    keyword,
-		Status:     statuses,
-		SortKey:    sortKey,
-		Page:       page,
-		PageSize:   pageSize,
-		AssignedTo: assignedTo,
+		Keyword:     keyword,
+		Status:      statuses,
+		SortKey:     sortKey,
+		Page:        page,
+		PageSize:    pageSize,
+		AssignedTo:  assignedTo,
+		ParentID:    parentID,
+		RootOnly:    rootOnly,
+		HideBlocked: hideBlocked,
+		WatchedBy:   watchedBy,
 	})
 	if err != nil {
 		h.respondServiceError(w, err)
@@ -113,7 +166,125 @@ func (h *Handler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, mapTask(t))
 }
 
+func (h *Handler) handleGetTaskActivity(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	result, err := h.services.Tasks.GetTaskActivity(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]activityDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, mapActivity(item))
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{
+		"items":       items,
+		"submitCount": result.SubmitCount,
+		"rejectCount": result.RejectCount,
+	})
+}
+
 func (h *Handler) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	h.createTask(w, r, nil)
+}
+
+func (h *Handler) handleListChildTasks(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	children, err := h.services.Tasks.ListChildTasks(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]taskDTO, 0, len(children))
+	for _, child := range children {
+		items = append(items, mapTask(child))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleCreateChildTask(w http.ResponseWriter, r *http.Request) {
+	parentID, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	h.createTask(w, r, &parentID)
+}
+
+func (h *Handler) handleAddTaskDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req addDependencyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+	blockerID, err := uuid.Parse(strings.TrimSpace(req.BlockedBy))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "前置任务 ID 不合法")
+		return
+	}
+
+	updated, err := h.services.Tasks.AddTaskDependency(r.Context(), id, blockerID, actor)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
+func (h *Handler) handleRemoveTaskDependency(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	blockerID, err := parseUUIDParam(r, "blockerId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "前置任务 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	updated, err := h.services.Tasks.RemoveTaskDependency(r.Context(), id, blockerID, actor)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
+func (h *Handler) createTask(w http.ResponseWriter, r *http.Request, parentID *uuid.UUID) {
 	userID, ok := CurrentUserID(r.Context())
 	if !ok {
 		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
@@ -132,14 +303,17 @@ func (h *Handler) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 	priority := task.Priority(strings.TrimSpace(req.Priority))
 
 	created, err := h.services.Tasks.CreateTask(r.Context(), service.TaskCreateInput{
-		Title:           req.Title,
-		DescriptionHTML: req.DescriptionHTML,
-		Bounty:          req.Bounty,
-		Priority:        priority,
-		Deadline:        deadline,
-		Tags:            tags,
-		CreatedBy:       userID,
-		Publish:         req.Publish,
+		Title:            req.Title,
+		DescriptionHTML:  req.DescriptionHTML,
+		Bounty:           req.Bounty,
+		Priority:         priority,
+		Deadline:         deadline,
+		Tags:             tags,
+		CreatedBy:        userID,
+		ParentID:         parentID,
+		RequireChecklist: req.RequireChecklist,
+		Checklist:        req.Checklist,
+		Publish:          req.Publish,
 	})
 	if err != nil {
 		h.respondServiceError(w, err)
@@ -156,13 +330,19 @@ func (h *Handler) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
 	var req updateTaskRequest
 	if err := decodeJSON(r, &req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
 		return
 	}
 
-	input := service.TaskUpdateInput{ID: id}
+	input := service.TaskUpdateInput{ID: id, ActorID: actor, RequireChecklist: req.RequireChecklist}
 	if req.Title != nil {
 		input.Title = req.Title
 	}
@@ -211,7 +391,13 @@ func (h *Handler) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.services.Tasks.DeleteTask(r.Context(), id); err != nil {
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Tasks.DeleteTask(r.Context(), id, actor); err != nil {
 		h.respondServiceError(w, err)
 		return
 	}
@@ -282,6 +468,40 @@ func (h *Handler) handleClaimTask(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, mapTask(updated))
 }
 
+func (h *Handler) handleWatchTask(w http.ResponseWriter, r *http.Request) {
+	h.setTaskWatching(w, r, true)
+}
+
+func (h *Handler) handleUnwatchTask(w http.ResponseWriter, r *http.Request) {
+	h.setTaskWatching(w, r, false)
+}
+
+func (h *Handler) setTaskWatching(w http.ResponseWriter, r *http.Request, watch bool) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "任务 ID 不合法")
+		return
+	}
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var updated task.Task
+	if watch {
+		updated, err = h.services.Tasks.WatchTask(r.Context(), id, userID)
+	} else {
+		updated, err = h.services.Tasks.UnwatchTask(r.Context(), id, userID)
+	}
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTask(updated))
+}
+
 func (h *Handler) handleReleaseTask(w http.ResponseWriter, r *http.Request) {
 	id, err := parseUUIDParam(r, "id")
 	if err != nil {
@@ -315,7 +535,16 @@ func (h *Handler) handleSubmitTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updated, err := h.services.Tasks.SubmitTask(r.Context(), id, userID)
+	var req submitTaskRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Tasks.SubmitTask(r.Context(), id, userID, service.TaskSubmissionInput{
+		Note:  req.Note,
+		Links: req.Links,
+	})
 	if err != nil {
 		h.respondServiceError(w, err)
 		return
@@ -336,9 +565,15 @@ func (h *Handler) handleCompleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req reviewTaskRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
 	roles := CurrentUserRoles(r.Context())
 
-	updated, err := h.services.Tasks.CompleteTask(r.Context(), id, userID, roles)
+	updated, err := h.services.Tasks.CompleteTask(r.Context(), id, userID, roles, req.Comment)
 	if err != nil {
 		h.respondServiceError(w, err)
 		return
@@ -359,9 +594,15 @@ func (h *Handler) handleRejectTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var req reviewTaskRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
 	roles := CurrentUserRoles(r.Context())
 
-	updated, err := h.services.Tasks.RejectTask(r.Context(), id, userID, roles)
+	updated, err := h.services.Tasks.RejectTask(r.Context(), id, userID, roles, req.Comment)
 	if err != nil {
 		h.respondServiceError(w, err)
 		return
@@ -0,0 +1,228 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/task"
+	"backend/internal/service"
+)
+
+type createTemplateRequest struct {
+	Title                 string   `json:"title"`
+	DescriptionHTML       string   `json:"descriptionHtml"`
+	Bounty                int64    `json:"bounty"`
+	Priority              string   `json:"priority"`
+	Tags                  []string `json:"tags"`
+	TagsText              string   `json:"tagsText"`
+	DeadlineOffsetMinutes int      `json:"deadlineOffsetMinutes"`
+}
+
+type updateTemplateRequest struct {
+	Title                 *string   `json:"title"`
+	DescriptionHTML       *string   `json:"descriptionHtml"`
+	Bounty                *int64    `json:"bounty"`
+	Priority              *string   `json:"priority"`
+	Tags                  *[]string `json:"tags"`
+	TagsText              *string   `json:"tagsText"`
+	DeadlineOffsetMinutes *int      `json:"deadlineOffsetMinutes"`
+}
+
+type instantiateTemplateRequest struct {
+	Title           *string   `json:"title"`
+	DescriptionHTML *string   `json:"descriptionHtml"`
+	Bounty          *int64    `json:"bounty"`
+	Priority        *string   `json:"priority"`
+	Deadline        *string   `json:"deadline"`
+	Tags            *[]string `json:"tags"`
+	Publish         bool      `json:"publish"`
+}
+
+func (h *Handler) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.services.Templates.ListTemplates(r.Context())
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]templateDTO, 0, len(templates))
+	for _, tpl := range templates {
+		items = append(items, mapTemplate(tpl))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "模板 ID 不合法")
+		return
+	}
+
+	tpl, err := h.services.Templates.GetTemplate(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTemplate(tpl))
+}
+
+func (h *Handler) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req createTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	created, err := h.services.Templates.CreateTemplate(r.Context(), service.TemplateInput{
+		Title:           req.Title,
+		DescriptionHTML: req.DescriptionHTML,
+		Bounty:          req.Bounty,
+		Priority:        task.Priority(strings.TrimSpace(req.Priority)),
+		Tags:            mergeTags(req.Tags, req.TagsText),
+		DeadlineOffset:  time.Duration(req.DeadlineOffsetMinutes) * time.Minute,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapTemplate(created))
+}
+
+func (h *Handler) handleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "模板 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	input := service.TemplateUpdateInput{
+		ID:              id,
+		ActorID:         actor,
+		Title:           req.Title,
+		DescriptionHTML: req.DescriptionHTML,
+		Bounty:          req.Bounty,
+	}
+	if req.Priority != nil {
+		priority := task.Priority(strings.TrimSpace(*req.Priority))
+		input.Priority = &priority
+	}
+	if req.Tags != nil || req.TagsText != nil {
+		tags := make([]string, 0)
+		if req.Tags != nil {
+			tags = mergeTags(*req.Tags, "")
+		}
+		if req.TagsText != nil {
+			tags = mergeTags(tags, *req.TagsText)
+		}
+		input.Tags = &tags
+	}
+	if req.DeadlineOffsetMinutes != nil {
+		offset := time.Duration(*req.DeadlineOffsetMinutes) * time.Minute
+		input.DeadlineOffset = &offset
+	}
+
+	updated, err := h.services.Templates.UpdateTemplate(r.Context(), input)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapTemplate(updated))
+}
+
+func (h *Handler) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "模板 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Templates.DeleteTemplate(r.Context(), id, actor); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleInstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "模板 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req instantiateTemplateRequest
+	if err := decodeOptionalJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	input := service.TemplateInstantiateInput{
+		TemplateID:      id,
+		ActorID:         actor,
+		Title:           req.Title,
+		DescriptionHTML: req.DescriptionHTML,
+		Bounty:          req.Bounty,
+		Publish:         req.Publish,
+	}
+	if req.Priority != nil {
+		priority := task.Priority(strings.TrimSpace(*req.Priority))
+		input.Priority = &priority
+	}
+	if req.Deadline != nil {
+		deadline := parseTime(*req.Deadline)
+		if deadline == nil {
+			respondError(w, http.StatusBadRequest, "invalid_time", "截止时间格式不正确")
+			return
+		}
+		input.Deadline = deadline
+	}
+	if req.Tags != nil {
+		tags := mergeTags(*req.Tags, "")
+		input.Tags = &tags
+	}
+
+	created, err := h.services.Templates.InstantiateTemplate(r.Context(), input)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapTask(created))
+}
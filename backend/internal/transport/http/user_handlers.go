@@ -3,6 +3,7 @@ package transporthttp
 import (
 	"net/http"
 
+	"backend/internal/domain/user"
 	"backend/internal/service"
 )
 
@@ -12,6 +13,11 @@ type updateProfileRequest struct {
 	Bio         string `json:"bio"`
 }
 
+type updateEmailSettingsRequest struct {
+	Email     string `json:"email"`
+	EmailMode string `json:"emailMode"`
+}
+
 type changePasswordRequest struct {
 	CurrentPassword string `json:"currentPassword"`
 	NewPassword     string `json:"newPassword"`
@@ -34,7 +40,13 @@ func (h *Handler) handleGetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, mapUser(profile))
+	unread, err := h.services.Notifications.UnreadCount(r.Context(), userID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, profileDTO{userDTO: mapUser(profile), EmailMode: string(profile.EmailMode), UnreadNotifications: unread})
 }
 
 func (h *Handler) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
@@ -63,6 +75,46 @@ func (h *Handler) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, mapUser(updated))
 }
 
+func (h *Handler) handleUpdateEmailSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateEmailSettingsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Users.UpdateEmailSettings(r.Context(), userID, service.EmailSettingsInput{
+		Email: req.Email,
+		Mode:  user.EmailMode(req.EmailMode),
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, emailSettingsDTO{Email: updated.Email, EmailMode: string(updated.EmailMode)})
+}
+
+func (h *Handler) handleSendTestEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Emails.SendTest(r.Context(), userID); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "测试邮件已发送"})
+}
+
 func (h *Handler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
 	userID, ok := CurrentUserID(r.Context())
 	if !ok {
@@ -0,0 +1,224 @@
+package transporthttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/internal/domain/webhook"
+	"backend/internal/service"
+)
+
+type createWebhookRequest struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"`
+}
+
+type updateWebhookRequest struct {
+	URL          *string   `json:"url"`
+	Secret       *string   `json:"secret"`
+	RotateSecret bool      `json:"rotateSecret"`
+	Events       *[]string `json:"events"`
+	Description  *string   `json:"description"`
+	Active       *bool     `json:"active"`
+}
+
+func (h *Handler) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.services.Webhooks.ListWebhooks(r.Context())
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]webhookDTO, 0, len(hooks))
+	for _, hook := range hooks {
+		items = append(items, mapWebhook(hook, false))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+func (h *Handler) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+
+	hook, err := h.services.Webhooks.GetWebhook(r.Context(), id)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapWebhook(hook, false))
+}
+
+func (h *Handler) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req createWebhookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	created, err := h.services.Webhooks.CreateWebhook(r.Context(), service.WebhookInput{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      active,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, mapWebhook(created, true))
+}
+
+func (h *Handler) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	var req updateWebhookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_payload", "请求格式不正确")
+		return
+	}
+
+	updated, err := h.services.Webhooks.UpdateWebhook(r.Context(), service.WebhookUpdateInput{
+		ID:           id,
+		ActorID:      actor,
+		URL:          req.URL,
+		Secret:       req.Secret,
+		RotateSecret: req.RotateSecret,
+		Events:       req.Events,
+		Description:  req.Description,
+		Active:       req.Active,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapWebhook(updated, req.RotateSecret))
+}
+
+func (h *Handler) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	if err := h.services.Webhooks.DeleteWebhook(r.Context(), id, actor); err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+
+	status := webhook.DeliveryStatus(strings.TrimSpace(r.URL.Query().Get("status")))
+	result, err := h.services.Webhooks.ListDeliveries(r.Context(), id, status, queryInt(r, "page", 1), queryInt(r, "pageSize", 20))
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	items := make([]webhookDeliveryDTO, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, mapWebhookDelivery(item, false))
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"items":    items,
+		"total":    result.Total,
+		"page":     result.Page,
+		"pageSize": result.PageSize,
+	})
+}
+
+func (h *Handler) handleGetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "投递记录 ID 不合法")
+		return
+	}
+
+	item, err := h.services.Webhooks.GetDelivery(r.Context(), id, deliveryID)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, mapWebhookDelivery(item, true))
+}
+
+func (h *Handler) handleRedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := parseUUIDParam(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "Webhook ID 不合法")
+		return
+	}
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryId"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_id", "投递记录 ID 不合法")
+		return
+	}
+
+	actor, ok := CurrentUserID(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "unauthorized", "未授权访问")
+		return
+	}
+
+	item, err := h.services.Webhooks.Redeliver(r.Context(), id, deliveryID, actor)
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, mapWebhookDelivery(item, false))
+}
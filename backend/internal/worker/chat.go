@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/service"
+)
+
+// ChatWorker 定期发送到期的群机器人推送，包括首次推送与失败后的重试。
+type ChatWorker struct {
+	cfg   config.ChatConfig
+	chats *service.ChatService
+	log   *zap.Logger
+	loop  loop
+}
+
+// NewChatWorker 构造群机器人推送任务。
+func NewChatWorker(cfg config.ChatConfig, chats *service.ChatService, log *zap.Logger) *ChatWorker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &ChatWorker{cfg: cfg, chats: chats, log: log}
+}
+
+// Start 在后台启动推送，重复调用不会启动多个实例。
+func (w *ChatWorker) Start(ctx context.Context) {
+	if w == nil || !w.cfg.Enabled {
+		return
+	}
+	if w.loop.start(ctx, w.cfg.Interval, w.runOnce) {
+		w.log.Info("chat worker started",
+			zap.Duration("interval", w.cfg.Interval),
+			zap.Int("max_attempts", w.cfg.MaxAttempts),
+		)
+	}
+}
+
+// Stop 通知推送退出并等待进行中的请求结束，ctx 到期后不再等待。
+func (w *ChatWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.loop.stop(ctx); err != nil {
+		w.log.Warn("chat worker stop timed out", zap.Error(err))
+	}
+}
+
+// runOnce 在积压较多时连续处理，直到本轮没有到期推送。
+func (w *ChatWorker) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		sent, err := w.chats.DeliverDue(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Error("deliver chat messages failed", zap.Error(err))
+			}
+			return
+		}
+		if sent == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/service"
+)
+
+// DeadlineWorker 定期巡检任务截止时间：提醒即将截止的领取、标记逾期领取，并按配置自动释放超过宽限期的领取。
+type DeadlineWorker struct {
+	cfg           config.DeadlineConfig
+	tasks         *service.TaskService
+	notifications *service.NotificationService
+	log           *zap.Logger
+	loop          loop
+}
+
+// NewDeadlineWorker 构造截止时间巡检任务。
+func NewDeadlineWorker(cfg config.DeadlineConfig, tasks *service.TaskService, notifications *service.NotificationService, log *zap.Logger) *DeadlineWorker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &DeadlineWorker{cfg: cfg, tasks: tasks, notifications: notifications, log: log}
+}
+
+// Start 在后台启动巡检，重复调用不会启动多个实例。
+func (w *DeadlineWorker) Start(ctx context.Context) {
+	if w == nil || !w.cfg.Enabled {
+		return
+	}
+	if w.loop.start(ctx, w.cfg.Interval, w.runOnce) {
+		w.log.Info("deadline worker started",
+			zap.Duration("interval", w.cfg.Interval),
+			zap.Bool("auto_release", w.cfg.AutoRelease),
+			zap.Duration("release_grace", w.cfg.ReleaseGrace),
+			zap.Duration("remind_before", w.cfg.RemindBefore),
+		)
+	}
+}
+
+// Stop 通知巡检退出并等待当前一轮处理结束，ctx 到期后不再等待。
+func (w *DeadlineWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.loop.stop(ctx); err != nil {
+		w.log.Warn("deadline worker stop timed out", zap.Error(err))
+	}
+}
+
+func (w *DeadlineWorker) runOnce(ctx context.Context) {
+	now := time.Now().UTC()
+
+	reminded := 0
+	if w.notifications != nil && w.cfg.RemindBefore > 0 {
+		var err error
+		reminded, err = w.notifications.NotifyDeadlines(ctx, now, w.cfg.RemindBefore)
+		if err != nil && ctx.Err() == nil {
+			w.log.Error("deadline reminders failed", zap.Error(err))
+		}
+	}
+
+	flagged, err := w.tasks.FlagOverdueTasks(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Error("flag overdue tasks failed", zap.Error(err))
+		}
+		return
+	}
+
+	released := 0
+	if w.cfg.AutoRelease {
+		released, err = w.tasks.ReleaseStaleClaims(ctx, now, w.cfg.ReleaseGrace)
+		if err != nil && ctx.Err() == nil {
+			w.log.Error("release stale claims failed", zap.Error(err))
+		}
+	}
+
+	if reminded > 0 || flagged > 0 || released > 0 {
+		w.log.Info("deadline check finished", zap.Int("reminded", reminded), zap.Int("flagged", flagged), zap.Int("released", released))
+	}
+}
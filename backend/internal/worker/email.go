@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/service"
+)
+
+// EmailWorker 定期发送邮件通知：即时模式的待发邮件与到达汇总时刻的每日汇总。
+type EmailWorker struct {
+	cfg    config.MailConfig
+	emails *service.EmailService
+	log    *zap.Logger
+	loop   loop
+}
+
+// NewEmailWorker 构造邮件通知任务。
+func NewEmailWorker(cfg config.MailConfig, emails *service.EmailService, log *zap.Logger) *EmailWorker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &EmailWorker{cfg: cfg, emails: emails, log: log}
+}
+
+// Start 在后台启动发送，未启用邮件时直接返回，重复调用不会启动多个实例。
+func (w *EmailWorker) Start(ctx context.Context) {
+	if w == nil || !w.cfg.Enabled {
+		return
+	}
+	if w.loop.start(ctx, w.cfg.Interval, w.runOnce) {
+		w.log.Info("email worker started",
+			zap.Duration("interval", w.cfg.Interval),
+			zap.String("smtp_host", w.cfg.Host),
+			zap.Int("digest_hour", w.cfg.DigestHour),
+		)
+	}
+}
+
+// Stop 通知发送退出并等待进行中的 SMTP 会话结束，ctx 到期后不再等待。
+func (w *EmailWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.loop.stop(ctx); err != nil {
+		w.log.Warn("email worker stop timed out", zap.Error(err))
+	}
+}
+
+// runOnce 先清空即时邮件积压，再发送到期的每日汇总。
+func (w *EmailWorker) runOnce(ctx context.Context) {
+	instant := 0
+	for ctx.Err() == nil {
+		sent, err := w.emails.SendInstant(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Error("send notification emails failed", zap.Error(err))
+			}
+			break
+		}
+		if sent == 0 {
+			break
+		}
+		instant += sent
+	}
+
+	digests, err := w.emails.SendDigests(ctx, time.Now())
+	if err != nil && ctx.Err() == nil {
+		w.log.Error("send digest emails failed", zap.Error(err))
+	}
+
+	if instant > 0 || digests > 0 {
+		w.log.Info("email notifications sent", zap.Int("instant", instant), zap.Int("digests", digests))
+	}
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/domain/outbox"
+	"backend/internal/repository"
+)
+
+// outboxLease 为领取一批事件后的租约时长，需覆盖整批分发的耗时，到期未记录结果的事件会被重新领取。
+const outboxLease = time.Minute
+
+// OutboxDispatcher 从发件箱领取任务事件并依次交给各分发目标，全部成功后删除事件；
+// 部分目标失败时只记录已送达的目标，稍后仅向失败的目标重试。进程在记录结果前退出时事件会被再次分发（至少一次）。
+type OutboxDispatcher struct {
+	cfg     config.OutboxConfig
+	repo    repository.OutboxRepository
+	targets []outbox.Target
+	log     *zap.Logger
+	loop    loop
+}
+
+// NewOutboxDispatcher 构造发件箱分发器。
+func NewOutboxDispatcher(cfg config.OutboxConfig, repo repository.OutboxRepository, targets []outbox.Target, log *zap.Logger) *OutboxDispatcher {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &OutboxDispatcher{cfg: cfg, repo: repo, targets: targets, log: log}
+}
+
+// Start 在后台启动分发，重复调用不会启动多个实例。
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	if d.loop.start(ctx, d.cfg.Interval, d.runOnce) {
+		d.log.Info("outbox dispatcher started", zap.Duration("interval", d.cfg.Interval), zap.Int("batch_size", d.cfg.BatchSize))
+	}
+}
+
+// Stop 通知分发退出并等待当前批次结束，ctx 到期后不再等待；未记录结果的事件在租约到期后重新分发。
+func (d *OutboxDispatcher) Stop(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	if err := d.loop.stop(ctx); err != nil {
+		d.log.Warn("outbox dispatcher stop timed out", zap.Error(err))
+	}
+}
+
+// runOnce 连续处理整批事件，直到某一批不满为止，避免积压时每个间隔只处理一批。
+func (d *OutboxDispatcher) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := d.repo.Dispatch(ctx, d.cfg.BatchSize, outboxLease, d.handle, d.retryDelay)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.log.Error("dispatch outbox failed", zap.Error(err))
+			}
+			return
+		}
+		if n < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// handle 将事件交给尚未送达的目标，返回累计已送达的目标名称。
+func (d *OutboxDispatcher) handle(ctx context.Context, msg outbox.Message) ([]string, error) {
+	delivered := slices.Clone(msg.Delivered)
+	var errs []error
+	for _, target := range d.targets {
+		if slices.Contains(msg.Delivered, target.Name) {
+			continue
+		}
+		if err := target.Sink.Handle(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", target.Name, err))
+			continue
+		}
+		delivered = append(delivered, target.Name)
+	}
+	err := errors.Join(errs...)
+	if err != nil && ctx.Err() == nil {
+		d.log.Warn("outbox message delivery failed",
+			zap.Int64("id", msg.ID),
+			zap.String("topic", msg.Topic),
+			zap.Int("attempts", msg.Attempts+1),
+			zap.Error(err),
+		)
+	}
+	return delivered, err
+}
+
+// retryDelay 从 1 秒起逐次翻倍，最长 5 分钟；事件不会被丢弃，直到分发成功。
+func (d *OutboxDispatcher) retryDelay(attempts int) time.Duration {
+	delay := time.Second
+	for i := 1; i < attempts && delay < 5*time.Minute; i++ {
+		delay *= 2
+	}
+	return min(delay, 5*time.Minute)
+}
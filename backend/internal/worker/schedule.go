@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/service"
+)
+
+// ScheduleWorker 定期检查到期的周期计划并发布任务。
+type ScheduleWorker struct {
+	cfg       config.ScheduleConfig
+	schedules *service.ScheduleService
+	log       *zap.Logger
+	loop      loop
+}
+
+// NewScheduleWorker 构造周期任务调度器。
+func NewScheduleWorker(cfg config.ScheduleConfig, schedules *service.ScheduleService, log *zap.Logger) *ScheduleWorker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &ScheduleWorker{cfg: cfg, schedules: schedules, log: log}
+}
+
+// Start 在后台启动调度，重复调用不会启动多个实例。
+func (w *ScheduleWorker) Start(ctx context.Context) {
+	if w == nil || !w.cfg.Enabled {
+		return
+	}
+	if w.loop.start(ctx, w.cfg.Interval, w.runOnce) {
+		w.log.Info("schedule worker started", zap.Duration("interval", w.cfg.Interval))
+	}
+}
+
+// Stop 通知调度退出并等待当前一轮处理结束，ctx 到期后不再等待。
+func (w *ScheduleWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.loop.stop(ctx); err != nil {
+		w.log.Warn("schedule worker stop timed out", zap.Error(err))
+	}
+}
+
+func (w *ScheduleWorker) runOnce(ctx context.Context) {
+	if _, err := w.schedules.RunDue(ctx); err != nil && ctx.Err() == nil {
+		w.log.Error("run due schedules failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"backend/internal/config"
+	"backend/internal/service"
+)
+
+// WebhookWorker 定期发送到期的 webhook 投递，包括首次投递与失败后的重试。
+type WebhookWorker struct {
+	cfg      config.WebhookConfig
+	webhooks *service.WebhookService
+	log      *zap.Logger
+	loop     loop
+}
+
+// NewWebhookWorker 构造 webhook 投递任务。
+func NewWebhookWorker(cfg config.WebhookConfig, webhooks *service.WebhookService, log *zap.Logger) *WebhookWorker {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	return &WebhookWorker{cfg: cfg, webhooks: webhooks, log: log}
+}
+
+// Start 在后台启动投递，重复调用不会启动多个实例。
+func (w *WebhookWorker) Start(ctx context.Context) {
+	if w == nil || !w.cfg.Enabled {
+		return
+	}
+	if w.loop.start(ctx, w.cfg.Interval, w.runOnce) {
+		w.log.Info("webhook worker started",
+			zap.Duration("interval", w.cfg.Interval),
+			zap.Int("max_attempts", w.cfg.MaxAttempts),
+		)
+	}
+}
+
+// Stop 通知投递退出并等待进行中的请求结束，ctx 到期后不再等待。
+func (w *WebhookWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if err := w.loop.stop(ctx); err != nil {
+		w.log.Warn("webhook worker stop timed out", zap.Error(err))
+	}
+}
+
+// runOnce 在积压较多时连续处理，直到本轮没有到期投递。
+func (w *WebhookWorker) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		sent, err := w.webhooks.DeliverDue(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				w.log.Error("deliver webhooks failed", zap.Error(err))
+			}
+			return
+		}
+		if sent == 0 {
+			return
+		}
+	}
+}
@@ -423,8 +423,10 @@ export function useTaskBoard() {
   }
 
   const handleSubmitCompletion = async (task) => {
+    const note = window.prompt(`提交「${task.title}」验收，可填写完成说明：`, '')
+    if (note === null) return
     try {
-      await submitTaskProgress(task.id)
+      await submitTaskProgress(task.id, { note })
       await loadTasks()
     } catch (error) {
       console.error('提交任务失败', error)
@@ -442,8 +444,10 @@ export function useTaskBoard() {
   }
 
   const handleRejectCompletion = async (task) => {
+    const comment = window.prompt(`请填写退回「${task.title}」的原因：`, '')
+    if (comment === null || !comment.trim()) return
     try {
-      await rejectTaskSubmission(task.id)
+      await rejectTaskSubmission(task.id, { comment: comment.trim() })
       await loadTasks()
     } catch (error) {
       console.error('拒绝接收失败', error)
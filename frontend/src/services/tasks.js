@@ -51,20 +51,23 @@ export async function publishTask(taskId) {
   })
 }
 
-export async function submitTaskProgress(taskId) {
+export async function submitTaskProgress(taskId, payload = {}) {
   return requestJSON(`/api/v1/tasks/${taskId}/submit`, {
-    method: 'POST'
+    method: 'POST',
+    body: payload
   })
 }
 
-export async function verifyTaskCompletion(taskId) {
+export async function verifyTaskCompletion(taskId, payload = {}) {
   return requestJSON(`/api/v1/tasks/${taskId}/complete`, {
-    method: 'POST'
+    method: 'POST',
+    body: payload
   })
 }
 
-export async function rejectTaskSubmission(taskId) {
+export async function rejectTaskSubmission(taskId, payload = {}) {
   return requestJSON(`/api/v1/tasks/${taskId}/reject`, {
-    method: 'POST'
+    method: 'POST',
+    body: payload
   })
 }
@@ -18,6 +18,15 @@ export async function changePassword(payload) {
   })
 }
 
+export async function fetchMyPoints(params = {}) {
+  const searchParams = new URLSearchParams()
+  if (params.page) searchParams.set('page', String(params.page))
+  if (params.pageSize) searchParams.set('pageSize', String(params.pageSize))
+
+  const query = searchParams.toString()
+  return requestJSON(`/api/v1/users/me/points${query ? `?${query}` : ''}`)
+}
+
 export async function listAccounts(params = {}) {
   const searchParams = new URLSearchParams()
   if (params.keyword) searchParams.set('keyword', params.keyword)
@@ -34,3 +43,10 @@ export async function toggleAdmin(accountId, grant) {
     body: { grant }
   })
 }
+
+export async function adjustPoints(accountId, payload) {
+  return requestJSON(`/api/v1/users/${accountId}/points/adjust`, {
+    method: 'POST',
+    body: payload
+  })
+}